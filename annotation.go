@@ -0,0 +1,234 @@
+package knife4g
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// AnnotationValueType 自定义标注值的解析类型
+type AnnotationValueType int
+
+const (
+	AnnotationString AnnotationValueType = iota // 原样保留的字符串
+	AnnotationNumber                            // 数值，解析为 float64
+	AnnotationBool                              // 布尔值，空值视为 true
+	AnnotationList                              // 逗号分隔的字符串列表，可用方括号包裹
+)
+
+// AnnotationTarget 自定义标注的作用对象，可按位组合
+type AnnotationTarget int
+
+const (
+	AnnotationOnSchema AnnotationTarget = 1 << iota
+	AnnotationOnOperation
+	AnnotationOnParameter
+
+	AnnotationOnAll = AnnotationOnSchema | AnnotationOnOperation | AnnotationOnParameter
+)
+
+// Annotation 描述一个自定义的 @name: value 注释标注
+type Annotation struct {
+	Name      string
+	ValueType AnnotationValueType
+	Targets   AnnotationTarget                   // 为 0 时作用于全部对象
+	Apply     func(ctx *AnnotationContext) error // 修改输出的 Schema、Operation 或 Parameter
+}
+
+// AnnotationContext 自定义标注处理函数的上下文，提供对当前输出对象的读写
+type AnnotationContext struct {
+	Target AnnotationTarget
+	Name   string
+	Raw    string // 标注的原始文本
+	Value  any    // 按 ValueType 解析后的值：string、float64、bool 或 []string
+	Parser *CommentParser
+
+	output *orderedMap
+	badges []string
+}
+
+// Get 读取输出对象中的字段
+func (c *AnnotationContext) Get(key string) (any, bool) {
+	return c.output.Get(key)
+}
+
+// Set 写入输出对象中的字段
+func (c *AnnotationContext) Set(key string, value any) {
+	c.output.Set(key, value)
+}
+
+// SetExtension 写入 x- 扩展字段，name 未带 x- 前缀时自动补齐
+func (c *AnnotationContext) SetExtension(name string, value any) {
+	if !strings.HasPrefix(name, "x-") {
+		name = "x-" + name
+	}
+	c.output.Set(name, value)
+}
+
+// AddBadge 在描述末尾追加一个徽标，Knife4j 以 Markdown 行内代码样式渲染
+func (c *AnnotationContext) AddBadge(label string) {
+	c.badges = append(c.badges, label)
+}
+
+// builtinAnnotations 由 CommentParser 内置处理的标注名称，不允许重复注册
+var builtinAnnotations = map[string]bool{
+	TagConsumes: true, TagProduces: true, TagFile: true, TagDescription: true, TagSummary: true, TagOperationID: true, TagTags: true, TagOrder: true,
+	"enum": true, "minLength": true, "maxLength": true, "minimum": true, "maximum": true, "pattern": true, "format": true,
+	"required": true, "example": true, "response": true, "responseHeader": true, "request": true,
+	TagDeprecated: true, TagHidden: true, TagInternal: true,
+	"default": true, "const": true, "multipleOf": true, "minItems": true, "maxItems": true, "uniqueItems": true,
+	"exclusiveMinimum": true, "exclusiveMaximum": true, "nullable": true, "readOnly": true, "writeOnly": true,
+	ParamInHeader: true, ParamInCookie: true, ParamInQuery: true, TagParam: true, TagRouter: true,
+	"oneOf": true, "anyOf": true, "discriminator": true,
+}
+
+var annotationRegistry = struct {
+	sync.RWMutex
+	annotations map[string]Annotation
+}{annotations: make(map[string]Annotation)}
+
+// RegisterAnnotation 注册自定义标注，名称为空、与内置标注冲突或已注册时返回错误
+func RegisterAnnotation(annotation Annotation) error {
+	if annotation.Name == "" || strings.ContainsAny(annotation.Name, " :@[]") {
+		return fmt.Errorf("invalid annotation name %q", annotation.Name)
+	}
+	if annotation.Apply == nil {
+		return fmt.Errorf("annotation %q has no Apply function", annotation.Name)
+	}
+	if builtinAnnotations[annotation.Name] {
+		return fmt.Errorf("annotation %q is built in", annotation.Name)
+	}
+	if annotation.Targets == 0 {
+		annotation.Targets = AnnotationOnAll
+	}
+
+	annotationRegistry.Lock()
+	defer annotationRegistry.Unlock()
+	if _, exists := annotationRegistry.annotations[annotation.Name]; exists {
+		return fmt.Errorf("annotation %q is already registered", annotation.Name)
+	}
+	annotationRegistry.annotations[annotation.Name] = annotation
+	return nil
+}
+
+// MustRegisterAnnotation 同 RegisterAnnotation，注册失败时 panic，适合在 init 中使用
+func MustRegisterAnnotation(annotation Annotation) {
+	if err := RegisterAnnotation(annotation); err != nil {
+		panic(err)
+	}
+}
+
+// LookupAnnotation 查找已注册的自定义标注
+func LookupAnnotation(name string) (Annotation, bool) {
+	annotationRegistry.RLock()
+	defer annotationRegistry.RUnlock()
+	annotation, exists := annotationRegistry.annotations[name]
+	return annotation, exists
+}
+
+// RegisteredAnnotations 按名称顺序返回全部已注册的自定义标注
+func RegisteredAnnotations() []Annotation {
+	annotationRegistry.RLock()
+	defer annotationRegistry.RUnlock()
+	result := make([]Annotation, 0, len(annotationRegistry.annotations))
+	for _, annotation := range annotationRegistry.annotations {
+		result = append(result, annotation)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// ExtensionAnnotation 创建一个将值输出为 x-<name> 扩展并在描述中显示 "name: value" 徽标的标注
+func ExtensionAnnotation(name string, valueType AnnotationValueType) Annotation {
+	return Annotation{
+		Name:      name,
+		ValueType: valueType,
+		Apply: func(ctx *AnnotationContext) error {
+			ctx.SetExtension(ctx.Name, ctx.Value)
+			ctx.AddBadge(ctx.Name + ": " + ctx.Raw)
+			return nil
+		},
+	}
+}
+
+// parseAnnotationValue 按值类型解析自定义标注的文本
+func parseAnnotationValue(valueType AnnotationValueType, raw string) (any, error) {
+	switch valueType {
+	case AnnotationNumber:
+		num, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, errors.New("expected a number")
+		}
+		return num, nil
+	case AnnotationBool:
+		if raw == "" {
+			return true, nil
+		}
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, errors.New("expected true or false")
+		}
+		return b, nil
+	case AnnotationList:
+		values := strings.Split(strings.Trim(raw, "[]"), ",")
+		for i, v := range values {
+			values[i] = strings.TrimSpace(v)
+		}
+		return values, nil
+	default:
+		return raw, nil
+	}
+}
+
+// customAnnotation 记录注释中出现的一次自定义标注
+type customAnnotation struct {
+	name       string
+	annotation Annotation
+	raw        string
+	value      any
+}
+
+// applyAnnotations 对输出对象依次执行注释中出现的、作用于 target 的自定义标注，并将徽标追加到描述末尾。
+// 单个标注处理失败不影响其余标注，全部错误合并返回
+func applyAnnotations(target AnnotationTarget, parser *CommentParser, output *orderedMap) error {
+	var badges []string
+	var errs []error
+	for _, custom := range parser.custom {
+		if custom.annotation.Targets&target == 0 {
+			continue
+		}
+		ctx := &AnnotationContext{
+			Target: target,
+			Name:   custom.annotation.Name,
+			Raw:    custom.raw,
+			Value:  custom.value,
+			Parser: parser,
+			output: output,
+		}
+		if err := custom.annotation.Apply(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("@%s: %w", custom.annotation.Name, err))
+			continue
+		}
+		badges = append(badges, ctx.badges...)
+	}
+	if len(badges) == 0 {
+		return errors.Join(errs...)
+	}
+
+	line := make([]string, len(badges))
+	for i, badge := range badges {
+		line[i] = "`" + badge + "`"
+	}
+	description, _ := output.Get("description")
+	text, _ := description.(string)
+	if text != "" {
+		text += "\n\n"
+	}
+	output.Set("description", text+strings.Join(line, " "))
+	return errors.Join(errs...)
+}
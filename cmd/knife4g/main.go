@@ -0,0 +1,84 @@
+// knife4g 命令行工具。
+//
+// scan 子命令扫描 Go 源码中处理函数的文档注释，按 @router、@summary、@tags、@description、
+// @param、@request 与 @response 等标注生成 OpenAPI 3.0 文档，请求与响应引用的结构体经 go/types
+// 解析为 components.schemas：
+//
+//	knife4g scan -o openapi.yaml ./...
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+)
+
+const usage = `Usage:
+
+	knife4g scan [-o openapi.yaml] [-title title] [-version version] [packages]
+
+Commands:
+
+	scan    build an OpenAPI document from handler doc comments (default packages: ./...)
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	switch os.Args[1] {
+	case "scan":
+		if err := runScan(os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "knife4g scan: %v\n", err)
+			os.Exit(1)
+		}
+	case "help", "-h", "-help", "--help":
+		fmt.Fprint(os.Stdout, usage)
+	default:
+		fmt.Fprintf(os.Stderr, "knife4g: unknown command %q\n\n%s", os.Args[1], usage)
+		os.Exit(2)
+	}
+}
+
+// runScan 执行 scan 子命令
+func runScan(args []string) error {
+	flags := flag.NewFlagSet("scan", flag.ExitOnError)
+	output := flags.String("o", "openapi.yaml", "output file, - for stdout")
+	title := flags.String("title", "", "info.title of the document (default: main module path)")
+	version := flags.String("version", "0.0.1", "info.version of the document")
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), usage, "\nFlags:\n")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	patterns := flags.Args()
+	if len(patterns) == 0 {
+		patterns = []string{"./..."}
+	}
+
+	s := newScanner()
+	doc, err := s.scan(patterns)
+	if err != nil {
+		return err
+	}
+	if *title != "" {
+		doc.Info.Title = *title
+	}
+	doc.Info.Version = *version
+	for _, warning := range s.warnings {
+		fmt.Fprintf(os.Stderr, "knife4g scan: %s\n", warning)
+	}
+
+	data, err := marshalDocument(doc)
+	if err != nil {
+		return err
+	}
+	if *output == "-" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	return os.WriteFile(*output, data, 0o644)
+}
@@ -0,0 +1,318 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/snac21/knife4g"
+)
+
+// listedPackage 为 go list -json 输出中本工具使用的字段
+type listedPackage struct {
+	ImportPath string
+	Name       string
+	Dir        string
+	GoFiles    []string
+	Module     *struct{ Path string }
+}
+
+// scanner 扫描 Go 包中带 @router 标注的处理函数并构建 OpenAPI 文档
+type scanner struct {
+	fset        *token.FileSet
+	importer    types.Importer
+	doc         *knife4g.OpenAPI3
+	scanned     map[string]bool           // 本次扫描的包路径，其中的具名基础类型会收集同类型常量作为枚举
+	schemaNames map[string]string         // 已生成的结构体类型（含类型实参）到 components.schemas 名称
+	docs        map[string]map[int]string // 文件名到 "声明位置偏移 → 文档注释" 的索引，按需解析
+	tags        map[string]bool
+	warnings    []string
+}
+
+func newScanner() *scanner {
+	fset := token.NewFileSet()
+	return &scanner{
+		fset:     fset,
+		importer: importer.ForCompiler(fset, "source", nil),
+		doc: &knife4g.OpenAPI3{
+			OpenAPI: "3.0.3",
+			Info:    knife4g.Info{Title: "API"},
+			Paths:   make(map[string]knife4g.PathItem),
+		},
+		scanned:     make(map[string]bool),
+		schemaNames: make(map[string]string),
+		docs:        make(map[string]map[int]string),
+		tags:        make(map[string]bool),
+	}
+}
+
+// scan 按 go list 的包模式扫描处理函数，包按 go list 的顺序、函数按源文件中的顺序输出
+func (s *scanner) scan(patterns []string) (*knife4g.OpenAPI3, error) {
+	packages, err := listPackages(patterns)
+	if err != nil {
+		return nil, err
+	}
+	for _, pkg := range packages {
+		s.scanned[pkg.ImportPath] = true
+	}
+	for i, pkg := range packages {
+		if i == 0 && pkg.Module != nil {
+			s.doc.Info.Title = pkg.Module.Path
+		}
+		if err := s.scanPackage(pkg); err != nil {
+			return nil, err
+		}
+	}
+	return s.doc, nil
+}
+
+// listPackages 调用 go list 解析包模式
+func listPackages(patterns []string) ([]listedPackage, error) {
+	args := append([]string{"list", "-json=ImportPath,Name,Dir,GoFiles,Module"}, patterns...)
+	cmd := exec.Command("go", args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("go list: %v\n%s", err, stderr.Bytes())
+	}
+
+	var packages []listedPackage
+	decoder := json.NewDecoder(bytes.NewReader(out))
+	for {
+		var pkg listedPackage
+		if err := decoder.Decode(&pkg); errors.Is(err, io.EOF) {
+			return packages, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("go list: %v", err)
+		}
+		packages = append(packages, pkg)
+	}
+}
+
+// scanPackage 解析并类型检查一个包，输出其中的处理函数。类型错误不会中止扫描，无法解析的类型在引用处给出警告
+func (s *scanner) scanPackage(listed listedPackage) error {
+	files := make([]*ast.File, 0, len(listed.GoFiles))
+	for _, name := range listed.GoFiles {
+		file, err := parser.ParseFile(s.fset, filepath.Join(listed.Dir, name), nil, parser.ParseComments)
+		if err != nil {
+			return err
+		}
+		files = append(files, file)
+	}
+
+	conf := types.Config{Importer: s.importer, Error: func(error) {}}
+	pkg, _ := conf.Check(listed.ImportPath, s.fset, files, nil)
+
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Doc == nil {
+				continue
+			}
+			annotations := knife4g.NewCommentParser().Parse(fn.Doc.Text())
+			if len(annotations.GetRoutes()) == 0 {
+				continue
+			}
+			for _, diagnostic := range annotations.Diagnostics() {
+				s.warn(s.fset.Position(fn.Pos()).String(), "%s", diagnostic)
+			}
+			for i, route := range annotations.GetRoutes() {
+				s.addOperation(pkg, fn, annotations, route, i)
+			}
+		}
+	}
+	return nil
+}
+
+// addOperation 按一条 @router 标注输出接口。@summary、@tags、@operationId、@param 与 @request、@response
+// 在生成时即已应用，完整注释写入描述，其余标注由 knife4g 在输出文档时解析
+func (s *scanner) addOperation(pkg *types.Package, fn *ast.FuncDecl, annotations *knife4g.CommentParser, route knife4g.RouteAnnotation, index int) {
+	name := fn.Name.Name
+	if fn.Recv != nil && len(fn.Recv.List) > 0 {
+		name = receiverName(fn.Recv.List[0].Type) + "_" + name
+	}
+	where := s.fset.Position(fn.Pos()).String()
+
+	op := &knife4g.Operation{
+		Summary:     annotations.GetString(knife4g.TagSummary),
+		Description: trimDocName(fn.Doc.Text(), fn.Name.Name),
+		OperationID: annotations.GetString(knife4g.TagOperationID),
+		Deprecated:  annotations.GetBool(knife4g.TagDeprecated),
+	}
+	if op.OperationID == "" {
+		op.OperationID = name
+	}
+	if index > 0 {
+		op.OperationID += fmt.Sprintf("_%d", index)
+	}
+
+	// 未声明 @tags 时按包名分组
+	for _, tag := range annotations.GetArray(knife4g.TagTags) {
+		if tag != "" {
+			op.Tags = append(op.Tags, tag)
+		}
+	}
+	if len(op.Tags) == 0 {
+		op.Tags = []string{pkg.Name()}
+	}
+	for _, tag := range op.Tags {
+		if !s.tags[tag] {
+			s.tags[tag] = true
+			s.doc.Tags = append(s.doc.Tags, knife4g.Tag{Name: tag})
+		}
+	}
+
+	// 参数：@param/@header/@cookie/@query 标注，路由中未声明的 path 参数按必填字符串补齐
+	declared := make(map[string]bool)
+	for _, param := range annotations.GetParameterAnnotations() {
+		op.Parameters = append(op.Parameters, param)
+		if param.In == knife4g.ParamInPath {
+			declared[param.Name] = true
+		}
+	}
+	for _, name := range pathParameters(route.Path) {
+		if !declared[name] {
+			op.Parameters = append(op.Parameters, knife4g.Parameter{
+				Name:     name,
+				In:       knife4g.ParamInPath,
+				Required: true,
+				Schema:   &knife4g.Schema{Type: knife4g.SchemaType{knife4g.ParamTypeString}},
+			})
+		}
+	}
+
+	// 请求体
+	if expr := annotations.GetString("request"); expr != "" {
+		if schema := s.resolve(pkg, fn.Pos(), expr, where); schema != nil {
+			contentType := knife4g.MIMEApplicationJSON
+			if consumes := annotations.GetArray(knife4g.TagConsumes); len(consumes) > 0 {
+				contentType = consumes[0]
+			}
+			op.RequestBody = &knife4g.RequestBody{
+				Content:  map[string]knife4g.MediaType{contentType: {Schema: schema}},
+				Required: true,
+			}
+		}
+	}
+
+	// 响应
+	op.Responses = make(map[string]knife4g.Response)
+	defaultType := knife4g.MIMEApplicationJSON
+	if produces := annotations.GetArray(knife4g.TagProduces); len(produces) > 0 {
+		defaultType = produces[0]
+	}
+	for _, annotation := range annotations.GetResponseAnnotations() {
+		response := knife4g.Response{Description: annotation.Description}
+		if response.Description == "" {
+			response.Description = knife4g.DefaultResponseDescription(annotation.Code)
+		}
+		if annotation.Schema != "" {
+			if schema := s.resolve(pkg, fn.Pos(), annotation.Schema, where); schema != nil {
+				contentType := annotation.ContentType
+				if contentType == "" {
+					contentType = defaultType
+				}
+				response.Content = map[string]knife4g.MediaType{contentType: {Schema: schema}}
+			}
+		}
+		op.Responses[annotation.Code] = response
+	}
+	if len(op.Responses) == 0 {
+		op.Responses["200"] = knife4g.Response{Description: knife4g.DefaultResponseDescription("200")}
+	}
+
+	item := s.doc.Paths[route.Path]
+	var existing **knife4g.Operation
+	switch route.Method {
+	case "get":
+		existing = &item.Get
+	case "post":
+		existing = &item.Post
+	case "put":
+		existing = &item.Put
+	case "patch":
+		existing = &item.Patch
+	case "delete":
+		existing = &item.Delete
+	}
+	if *existing != nil {
+		s.warn(where, "%s %s is already declared by %s, skipped", strings.ToUpper(route.Method), route.Path, (*existing).OperationID)
+		return
+	}
+	*existing = op
+	s.doc.SetPath(route.Path, item)
+}
+
+// resolve 在处理函数所在文件的作用域中解析 @request/@response 中的类型表达式，如 "User"、"[]model.Order"
+func (s *scanner) resolve(pkg *types.Package, pos token.Pos, expr, where string) *knife4g.Schema {
+	tv, err := types.Eval(s.fset, pkg, pos, expr)
+	if err != nil {
+		s.warn(where, "cannot resolve %q: %v", expr, err)
+		return nil
+	}
+	if !tv.IsType() {
+		s.warn(where, "%q is not a type", expr)
+		return nil
+	}
+	schema := s.typeSchema(tv.Type)
+	if schema == nil {
+		s.warn(where, "type %s cannot be encoded as JSON", tv.Type)
+	}
+	return schema
+}
+
+func (s *scanner) warn(where, format string, args ...any) {
+	s.warnings = append(s.warnings, where+": "+fmt.Sprintf(format, args...))
+}
+
+// receiverName 返回方法接收者的类型名称，忽略指针与类型参数
+func receiverName(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.StarExpr:
+		return receiverName(t.X)
+	case *ast.IndexExpr:
+		return receiverName(t.X)
+	case *ast.IndexListExpr:
+		return receiverName(t.X)
+	case *ast.Ident:
+		return t.Name
+	}
+	return ""
+}
+
+// pathParameters 按出现顺序返回路由中 {name} 形式的参数名
+func pathParameters(path string) []string {
+	var names []string
+	for {
+		start := strings.IndexByte(path, '{')
+		if start < 0 {
+			return names
+		}
+		end := strings.IndexByte(path[start:], '}')
+		if end < 0 {
+			return names
+		}
+		names = append(names, path[start+1:start+end])
+		path = path[start+end+1:]
+	}
+}
+
+// trimDocName 去掉 Go 文档注释开头的声明名称，如 "CreateUser 创建用户" 中的 "CreateUser"
+func trimDocName(text, name string) string {
+	text = strings.TrimSpace(text)
+	if rest, found := strings.CutPrefix(text, name+" "); found {
+		return strings.TrimSpace(rest)
+	}
+	return text
+}
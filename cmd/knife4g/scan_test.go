@@ -0,0 +1,68 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/snac21/knife4g"
+	"gopkg.in/yaml.v3"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files in testdata")
+
+// TestScanGolden 扫描 testdata/petapi 并与期望的文档逐字节比较，覆盖嵌入结构体、,string、json:"-"、
+// 泛型实例与枚举常量。修改生成逻辑后可使用 go test -run TestScanGolden -update 重新生成期望结果
+func TestScanGolden(t *testing.T) {
+	s := newScanner()
+	doc, err := s.scan([]string{"./testdata/petapi"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := marshalDocument(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const golden = "testdata/petapi.golden.yaml"
+	if *update {
+		if err := os.WriteFile(golden, data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != string(want) {
+		t.Errorf("scan output differs from %s:\n%s", golden, data)
+	}
+
+	var parsed knife4g.OpenAPI3
+	if err := yaml.Unmarshal(data, &parsed); err != nil {
+		t.Fatalf("generated document does not load: %v", err)
+	}
+	if got, want := parsed.Components.OrderedSchemas(), []string{"Page_Pet", "Pet", "Owner", "Page_Owner"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("schemas = %q, want %q", got, want)
+	}
+
+	dir, err := filepath.Abs("testdata/petapi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantWarnings := []string{
+		`handler.go:34:1: cannot resolve "Missing": eval:1:1: undefined: Missing`,
+		`handler.go:34:1: GET /pets is already declared by Handler_ListPets, skipped`,
+		`handler.go:34:1: cannot resolve "Missing": eval:1:1: undefined: Missing`,
+	}
+	var gotWarnings []string
+	for _, warning := range s.warnings {
+		gotWarnings = append(gotWarnings, strings.TrimPrefix(warning, dir+string(filepath.Separator)))
+	}
+	if !reflect.DeepEqual(gotWarnings, wantWarnings) {
+		t.Errorf("warnings = %q\nwant %q", gotWarnings, wantWarnings)
+	}
+}
@@ -0,0 +1,354 @@
+package main
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/snac21/knife4g"
+	"gopkg.in/yaml.v3"
+)
+
+const componentsSchemasPrefix = "#/components/schemas/"
+
+// typeSchema 按 encoding/json 的编码规则返回 Go 类型的 Schema，具名结构体以 $ref 引用 components.schemas。
+// 无法编码为 JSON 的类型（chan、func 等）返回 nil
+func (s *scanner) typeSchema(t types.Type) *knife4g.Schema {
+	switch t := types.Unalias(t).(type) {
+	case *types.Named:
+		return s.namedSchema(t)
+	case *types.Pointer:
+		return s.typeSchema(t.Elem())
+	case *types.Basic:
+		return basicSchema(t)
+	case *types.Slice:
+		if basic, ok := t.Elem().Underlying().(*types.Basic); ok && basic.Kind() == types.Byte {
+			return &knife4g.Schema{Type: knife4g.SchemaType{"string"}, Format: "byte"}
+		}
+		return s.arraySchema(t.Elem())
+	case *types.Array:
+		return s.arraySchema(t.Elem())
+	case *types.Map:
+		value := s.typeSchema(t.Elem())
+		if value == nil {
+			return nil
+		}
+		return &knife4g.Schema{Type: knife4g.SchemaType{"object"}, AdditionalProperties: &knife4g.SchemaOrBool{Schema: value}}
+	case *types.Struct:
+		return s.structSchema(t)
+	case *types.Interface:
+		return &knife4g.Schema{}
+	}
+	return nil
+}
+
+func (s *scanner) arraySchema(elem types.Type) *knife4g.Schema {
+	items := s.typeSchema(elem)
+	if items == nil {
+		return nil
+	}
+	return &knife4g.Schema{Type: knife4g.SchemaType{"array"}, Items: items}
+}
+
+// basicSchema 返回基础类型的 Schema
+func basicSchema(t *types.Basic) *knife4g.Schema {
+	switch t.Kind() {
+	case types.Bool:
+		return &knife4g.Schema{Type: knife4g.SchemaType{"boolean"}}
+	case types.Int, types.Int64, types.Uint, types.Uint64, types.Uintptr:
+		return &knife4g.Schema{Type: knife4g.SchemaType{"integer"}, Format: "int64"}
+	case types.Int8, types.Int16, types.Int32, types.Uint8, types.Uint16, types.Uint32:
+		return &knife4g.Schema{Type: knife4g.SchemaType{"integer"}, Format: "int32"}
+	case types.Float32:
+		return &knife4g.Schema{Type: knife4g.SchemaType{"number"}, Format: "float"}
+	case types.Float64:
+		return &knife4g.Schema{Type: knife4g.SchemaType{"number"}, Format: "double"}
+	case types.String:
+		return &knife4g.Schema{Type: knife4g.SchemaType{"string"}}
+	}
+	return nil
+}
+
+// namedSchema 返回具名类型的 Schema：time.Time 与实现 encoding.TextMarshaler 的类型编码为字符串，
+// 实现 json.Marshaler 的类型无法推断结构，结构体生成到 components.schemas，
+// 其他类型展开为底层类型，本次扫描的包中同类型的常量作为枚举
+func (s *scanner) namedSchema(t *types.Named) *knife4g.Schema {
+	obj := t.Obj()
+	if obj.Pkg() != nil && obj.Pkg().Path() == "time" && obj.Name() == "Time" {
+		return &knife4g.Schema{Type: knife4g.SchemaType{"string"}, Format: "date-time"}
+	}
+	if hasMethod(t, "MarshalJSON") {
+		return &knife4g.Schema{}
+	}
+	if hasMethod(t, "MarshalText") {
+		return &knife4g.Schema{Type: knife4g.SchemaType{"string"}}
+	}
+
+	if st, ok := t.Underlying().(*types.Struct); ok {
+		return s.componentSchema(t, func() *knife4g.Schema {
+			schema := s.structSchema(st)
+			schema.Description = trimDocName(s.docComment(obj.Pos()), obj.Name())
+			return schema
+		})
+	}
+
+	schema := s.typeSchema(t.Underlying())
+	if schema != nil && obj.Pkg() != nil && s.scanned[obj.Pkg().Path()] {
+		s.applyEnumConstants(schema, t)
+	}
+	return schema
+}
+
+// hasMethod 判断类型或其指针类型是否具有指定方法
+func hasMethod(t types.Type, name string) bool {
+	obj, _, _ := types.LookupFieldOrMethod(types.NewPointer(t), true, nil, name)
+	_, ok := obj.(*types.Func)
+	return ok
+}
+
+// schemaName 返回具名类型在 components.schemas 中的名称，泛型实例附加类型实参，如 "Page_User"
+func schemaName(t *types.Named) string {
+	name := t.Obj().Name()
+	args := t.TypeArgs()
+	for i := 0; args != nil && i < args.Len(); i++ {
+		arg := args.At(i)
+		for {
+			if p, ok := arg.(*types.Pointer); ok {
+				arg = p.Elem()
+				continue
+			}
+			break
+		}
+		if named, ok := types.Unalias(arg).(*types.Named); ok {
+			name += "_" + schemaName(named)
+		} else {
+			name += "_" + strings.NewReplacer("[]", "List_", "*", "", " ", "").Replace(arg.String())
+		}
+	}
+	return name
+}
+
+// componentSchema 返回指向 components.schemas 的引用，类型首次出现时以 build 生成其定义。
+// 先占位再生成，保证定义按首次引用的顺序排列，并避免循环引用导致的无限递归；
+// 名称已被其他包的同名类型占用时以包名作前缀
+func (s *scanner) componentSchema(t *types.Named, build func() *knife4g.Schema) *knife4g.Schema {
+	key := types.TypeString(t, nil)
+	if name, ok := s.schemaNames[key]; ok {
+		return &knife4g.Schema{Ref: componentsSchemasPrefix + name}
+	}
+	name := schemaName(t)
+	if _, taken := s.doc.Components.Schemas[name]; taken && t.Obj().Pkg() != nil {
+		name = t.Obj().Pkg().Name() + "_" + name
+	}
+	s.schemaNames[key] = name
+	s.doc.Components.SetSchema(name, knife4g.Schema{})
+	s.doc.Components.SetSchema(name, *build())
+	return &knife4g.Schema{Ref: componentsSchemasPrefix + name}
+}
+
+// marshalDocument 将文档编码为 YAML
+func marshalDocument(doc *knife4g.OpenAPI3) ([]byte, error) {
+	return yaml.Marshal(doc)
+}
+
+// structSchema 按 encoding/json 的规则生成结构体的对象 Schema：读取 json 标签中的名称与 string 选项，
+// 未命名的嵌入结构体字段提升到外层，binding 或 validate 标签含 required 的字段为必填，字段注释作为描述
+func (s *scanner) structSchema(st *types.Struct) *knife4g.Schema {
+	schema := &knife4g.Schema{Type: knife4g.SchemaType{"object"}}
+	for i := 0; i < st.NumFields(); i++ {
+		field := st.Field(i)
+		tag := reflect.StructTag(st.Tag(i))
+		jsonTag := tag.Get("json")
+		if jsonTag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(jsonTag, ",")
+
+		if field.Embedded() && name == "" {
+			if embedded, ok := derefType(field.Type()).Underlying().(*types.Struct); ok {
+				s.mergeEmbedded(schema, s.structSchema(embedded))
+				continue
+			}
+		}
+		if !field.Exported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name()
+		}
+
+		property := s.typeSchema(field.Type())
+		if property == nil {
+			continue
+		}
+		if hasOption(options, "string") && len(property.Type) > 0 && !property.Type.Is("object") && !property.Type.Is("array") {
+			property = &knife4g.Schema{Type: knife4g.SchemaType{"string"}}
+		}
+		if description := s.docComment(field.Pos()); description != "" {
+			property.Description = description
+		}
+		if hasOption(tag.Get("binding"), "required") || hasOption(tag.Get("validate"), "required") {
+			schema.Required = append(schema.Required, name)
+		}
+		schema.SetProperty(name, property)
+	}
+	return schema
+}
+
+// mergeEmbedded 将嵌入结构体的属性提升到外层，外层已有的同名字段优先
+func (s *scanner) mergeEmbedded(schema, embedded *knife4g.Schema) {
+	promoted := make(map[string]bool)
+	for _, name := range embedded.OrderedProperties() {
+		if _, exists := schema.Properties[name]; exists {
+			continue
+		}
+		schema.SetProperty(name, embedded.Properties[name])
+		promoted[name] = true
+	}
+	for _, name := range embedded.Required {
+		if promoted[name] {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+}
+
+func derefType(t types.Type) types.Type {
+	if p, ok := t.(*types.Pointer); ok {
+		return p.Elem()
+	}
+	return t
+}
+
+// hasOption 判断逗号分隔的标签选项中是否包含 option
+func hasOption(options, option string) bool {
+	for _, item := range strings.Split(options, ",") {
+		if strings.TrimSpace(item) == option {
+			return true
+		}
+	}
+	return false
+}
+
+// applyEnumConstants 以包中声明的同类型常量作为枚举：常量名写入 x-enum-varnames，常量注释写入 x-enum-descriptions
+func (s *scanner) applyEnumConstants(schema *knife4g.Schema, t *types.Named) {
+	scope := t.Obj().Pkg().Scope()
+	var consts []*types.Const
+	for _, name := range scope.Names() {
+		if c, ok := scope.Lookup(name).(*types.Const); ok && types.Identical(c.Type(), t) {
+			consts = append(consts, c)
+		}
+	}
+	if len(consts) == 0 {
+		return
+	}
+	sort.Slice(consts, func(i, j int) bool { return consts[i].Pos() < consts[j].Pos() })
+
+	names := make([]any, len(consts))
+	descriptions := make([]any, len(consts))
+	described := false
+	for i, c := range consts {
+		schema.Enum = append(schema.Enum, constantValue(c.Val()))
+		names[i] = c.Name()
+		text := strings.Join(strings.Fields(trimDocName(s.docComment(c.Pos()), c.Name())), " ")
+		descriptions[i] = text
+		described = described || text != ""
+	}
+	schema.Extensions.Set(knife4g.ExtEnumVarNames, names)
+	if described {
+		schema.Extensions.Set(knife4g.ExtEnumDescriptions, descriptions)
+	}
+}
+
+// constantValue 将常量值转换为 JSON 中的取值
+func constantValue(value constant.Value) any {
+	switch value.Kind() {
+	case constant.String:
+		return constant.StringVal(value)
+	case constant.Bool:
+		return constant.BoolVal(value)
+	case constant.Int:
+		if n, ok := constant.Int64Val(value); ok {
+			return n
+		}
+	case constant.Float:
+		if f, ok := constant.Float64Val(value); ok {
+			return f
+		}
+	}
+	return value.ExactString()
+}
+
+// docComment 返回声明位置处的文档注释与行尾注释。依赖包由 go/importer 从源码类型检查，未保留注释，
+// 因此按文件名重新解析并以字节偏移建立索引
+func (s *scanner) docComment(pos token.Pos) string {
+	if !pos.IsValid() {
+		return ""
+	}
+	position := s.fset.Position(pos)
+	index, ok := s.docs[position.Filename]
+	if !ok {
+		index = indexDocComments(position.Filename)
+		s.docs[position.Filename] = index
+	}
+	return index[position.Offset]
+}
+
+// indexDocComments 解析文件中类型、常量、变量与结构体字段的注释，以声明名称的字节偏移为键
+func indexDocComments(filename string) map[int]string {
+	index := make(map[int]string)
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, nil, parser.ParseComments)
+	if err != nil {
+		return index
+	}
+	add := func(names []*ast.Ident, pos token.Pos, groups ...*ast.CommentGroup) {
+		var parts []string
+		for _, group := range groups {
+			if text := strings.TrimSpace(group.Text()); text != "" {
+				parts = append(parts, text)
+			}
+		}
+		if len(parts) == 0 {
+			return
+		}
+		text := strings.Join(parts, "\n")
+		if len(names) == 0 {
+			index[fset.Position(pos).Offset] = text
+		}
+		for _, name := range names {
+			index[fset.Position(name.Pos()).Offset] = text
+		}
+	}
+
+	ast.Inspect(file, func(node ast.Node) bool {
+		switch n := node.(type) {
+		case *ast.GenDecl:
+			for _, spec := range n.Specs {
+				switch spec := spec.(type) {
+				case *ast.TypeSpec:
+					doc := spec.Doc
+					if doc == nil && len(n.Specs) == 1 {
+						doc = n.Doc
+					}
+					add([]*ast.Ident{spec.Name}, spec.Pos(), doc, spec.Comment)
+				case *ast.ValueSpec:
+					doc := spec.Doc
+					if doc == nil && len(n.Specs) == 1 {
+						doc = n.Doc
+					}
+					add(spec.Names, spec.Pos(), doc, spec.Comment)
+				}
+			}
+		case *ast.Field:
+			// 嵌入字段没有名称，go/types 以类型表达式的位置作为字段位置
+			add(n.Names, n.Type.Pos(), n.Doc, n.Comment)
+		}
+		return true
+	})
+	return index
+}
@@ -0,0 +1,34 @@
+package petapi
+
+// Handler 宠物接口
+type Handler struct{}
+
+// ListPets 查询宠物列表
+//
+// @router: /pets [get]
+// @summary: 宠物列表
+// @tags: 宠物
+// @query: status string "状态"
+// @response: 200: Page[Pet]
+func (h *Handler) ListPets() {}
+
+// CreatePet 新建宠物
+// @router: /pets [post]
+// @summary: 新建宠物
+// @tags: 宠物
+// @request: Pet
+// @response: 201: Pet | 已创建
+// @response: 400: | 参数错误
+func (h *Handler) CreatePet() {}
+
+// GetOwner 查询主人及其宠物
+// @router: /owners/{id} [get]
+// @router: /pets/{petId}/owner [get]
+// @response: 200: Page[*Owner]
+func GetOwner() {}
+
+// SearchPets 与 ListPets 路由重复，且响应类型不存在
+// @router: /pets [get]
+// @router: /pets/search [get]
+// @response: 200: Missing
+func SearchPets() {}
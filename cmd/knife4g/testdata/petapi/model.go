@@ -0,0 +1,66 @@
+// Package petapi 是 scan 子命令黄金测试使用的示例接口
+package petapi
+
+import "time"
+
+// Status 宠物状态
+type Status string
+
+const (
+	// StatusAvailable 可领养
+	StatusAvailable Status = "available"
+	StatusPending   Status = "pending"
+	StatusSold      Status = "sold" // 已售出
+)
+
+// Level 会员等级
+type Level int
+
+const (
+	LevelBasic Level = iota + 1
+	LevelGold
+)
+
+// Base 公共字段
+type Base struct {
+	ID        int64     `json:"id,string" binding:"required"`
+	CreatedAt time.Time `json:"createdAt"`
+	revision  int
+}
+
+// Audit 审计信息
+type Audit struct {
+	Operator string `json:"operator" validate:"required"`
+	Name     string `json:"name"` // 被外层同名字段覆盖
+}
+
+// Pet 宠物
+type Pet struct {
+	Base
+	*Audit
+	Name    string            `json:"name" validate:"required,max=32"` // 名称
+	Status  Status            `json:"status"`
+	Weight  float64           `json:"weight,string,omitempty"`
+	Tags    []string          `json:"tags,omitempty"`
+	Owner   *Owner            `json:"owner,omitempty"`
+	Photo   []byte            `json:"photo,omitempty"`
+	Extra   map[string]any    `json:"extra,omitempty"`
+	Labels  map[string]string `json:",omitempty"`
+	Secret  string            `json:"-"`
+	Dash    string            `json:"-,"`
+	Updates chan int          `json:"updates"`
+}
+
+// Owner 主人
+type Owner struct {
+	Name  string `json:"name"`
+	Level Level  `json:"level"`
+	// 名下宠物
+	Pets []Pet `json:"pets"`
+}
+
+// Page 分页结果
+type Page[T any] struct {
+	Items []T `json:"items"`
+	Total int `json:"total"`
+}
@@ -0,0 +1,312 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/snac21/knife4g"
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// generator 由 Proto 文件构建 OpenAPI 3.0 文档
+type generator struct {
+	options     options
+	doc         *knife4g.OpenAPI3
+	schemaNames map[protoreflect.FullName]string // 消息全名到 components.schemas 名称
+}
+
+func newGenerator(opts options) *generator {
+	return &generator{
+		options: opts,
+		doc: &knife4g.OpenAPI3{
+			OpenAPI: "3.0.3",
+			Info:    knife4g.Info{Title: opts.Title, Version: opts.Version},
+		},
+		schemaNames: make(map[protoreflect.FullName]string),
+	}
+}
+
+// generate 处理全部待生成文件中带 google.api.http 选项的方法
+func (g *generator) generate(files []*protogen.File) (*knife4g.OpenAPI3, error) {
+	var services []*protogen.Service
+	for _, file := range files {
+		if !file.Generate {
+			continue
+		}
+		for _, service := range file.Services {
+			added, err := g.addService(service)
+			if err != nil {
+				return nil, err
+			}
+			if added {
+				services = append(services, service)
+			}
+		}
+	}
+	if g.doc.Info.Title == "" {
+		g.doc.Info.Title = "API"
+		if len(services) == 1 {
+			g.doc.Info.Title = string(services[0].Desc.Name())
+		}
+	}
+	if g.doc.Paths == nil {
+		g.doc.Paths = make(map[string]knife4g.PathItem)
+	}
+	return g.doc, nil
+}
+
+// addService 将服务注册为 tag 并输出其 HTTP 接口，服务没有任何 HTTP 接口时返回 false。
+// 服务注释中的 @tags 指定 tag 名称，完整注释写入 tag 描述，由 knife4g 在输出时解析其余标注
+func (g *generator) addService(service *protogen.Service) (bool, error) {
+	comment := commentText(service.Comments.Leading, service.Comments.Trailing)
+	tag := string(service.Desc.Name())
+	if name := knife4g.NewCommentParser().Parse(comment).GetString(knife4g.TagTags); name != "" {
+		tag = name
+	}
+
+	added := false
+	for _, method := range service.Methods {
+		rule, err := methodHTTPRule(method.Desc.Options())
+		if err != nil {
+			return false, fmt.Errorf("%s: %v", method.Desc.FullName(), err)
+		}
+		if rule == nil {
+			continue
+		}
+		bindings := append([]httpRule{*rule}, rule.Additional...)
+		for i, binding := range bindings {
+			if binding.Path == "" {
+				continue
+			}
+			operationID := string(service.Desc.Name()) + "_" + string(method.Desc.Name())
+			if i > 0 {
+				operationID += fmt.Sprintf("_%d", i)
+			}
+			if err := g.addOperation(tag, operationID, method, binding); err != nil {
+				return false, fmt.Errorf("%s: %v", method.Desc.FullName(), err)
+			}
+			added = true
+		}
+	}
+	if added {
+		g.doc.Tags = append(g.doc.Tags, knife4g.Tag{Name: tag, Description: comment})
+	}
+	return added, nil
+}
+
+// addOperation 按 HTTP 绑定输出一个接口：路径模板中的字段为 path 参数，body 指定的字段为请求体，
+// 其余字段在没有 "*" 请求体时作为 query 参数，与 gRPC-Gateway 的映射规则一致
+func (g *generator) addOperation(tag, operationID string, method *protogen.Method, rule httpRule) error {
+	path, pathFields := convertPathTemplate(rule.Path)
+	comment := commentText(method.Comments.Leading, method.Comments.Trailing)
+	annotations := knife4g.NewOperationDescription(comment)
+
+	op := &knife4g.Operation{
+		Tags:        []string{tag},
+		Summary:     annotations.Summary,
+		Description: comment,
+		OperationID: operationID,
+		Deprecated:  isDeprecated(method.Desc.Options()),
+	}
+	if len(annotations.Tags) > 0 {
+		op.Tags = annotations.Tags
+	}
+	if annotations.OperationID != "" {
+		op.OperationID = annotations.OperationID
+	}
+
+	// path 参数
+	bound := make(map[string]bool) // 已绑定到 path 或请求体的字段路径
+	for _, name := range pathFields {
+		field := findField(method.Input, name)
+		param := knife4g.Parameter{Name: name, In: "path", Required: true, Schema: &knife4g.Schema{Type: knife4g.SchemaType{"string"}}}
+		if field != nil {
+			param.Schema = g.fieldSchema(field)
+			param.Description = commentText(field.Comments.Leading, field.Comments.Trailing)
+			param.Deprecated = isDeprecated(field.Desc.Options())
+		}
+		op.Parameters = append(op.Parameters, param)
+		bound[name] = true
+	}
+
+	// 请求体与 query 参数
+	switch rule.Body {
+	case "*":
+		if len(bound) == 0 {
+			op.RequestBody = jsonRequestBody(g.messageSchema(method.Input))
+			break
+		}
+		// 请求体中去掉已作为 path 参数的顶层字段
+		schema := g.objectSchema(method.Input, bound)
+		schema.Description = ""
+		op.RequestBody = jsonRequestBody(schema)
+	case "":
+		op.Parameters = append(op.Parameters, g.queryParameters(method.Input, "", "", bound, make(map[protoreflect.FullName]bool))...)
+	default:
+		field := findField(method.Input, rule.Body)
+		if field == nil {
+			return fmt.Errorf("body field %q not found in %s", rule.Body, method.Input.Desc.FullName())
+		}
+		op.RequestBody = jsonRequestBody(g.fieldSchema(field))
+		op.RequestBody.Description = commentText(field.Comments.Leading, field.Comments.Trailing)
+		bound[rule.Body] = true
+		op.Parameters = append(op.Parameters, g.queryParameters(method.Input, "", "", bound, make(map[protoreflect.FullName]bool))...)
+	}
+
+	// 响应
+	response := g.messageSchema(method.Output)
+	if rule.ResponseBody != "" {
+		field := findField(method.Output, rule.ResponseBody)
+		if field == nil {
+			return fmt.Errorf("response_body field %q not found in %s", rule.ResponseBody, method.Output.Desc.FullName())
+		}
+		response = g.fieldSchema(field)
+	}
+	op.Responses = map[string]knife4g.Response{
+		"200": {
+			Description: "OK",
+			Content:     map[string]knife4g.MediaType{knife4g.MIMEApplicationJSON: {Schema: response}},
+		},
+		"default": {
+			Description: "Default error response",
+			Content:     map[string]knife4g.MediaType{knife4g.MIMEApplicationJSON: {Schema: g.statusSchema()}},
+		},
+	}
+
+	item := g.doc.Paths[path]
+	switch rule.Method {
+	case "get":
+		item.Get = op
+	case "put":
+		item.Put = op
+	case "post":
+		item.Post = op
+	case "delete":
+		item.Delete = op
+	case "patch":
+		item.Patch = op
+	default:
+		fmt.Fprintf(os.Stderr, "protoc-gen-knife4g: %s: HTTP method %q is not supported, skipped\n", method.Desc.FullName(), rule.Method)
+		return nil
+	}
+	g.doc.SetPath(path, item)
+	return nil
+}
+
+// queryParameters 将消息中未绑定到 path 或请求体的字段展开为 query 参数，
+// 嵌套消息展开为 "parent.child" 形式，map 与 repeated 消息字段无法通过 query 传递而被跳过。
+// prefix 为参数名前缀，protoPrefix 为与 bound 中字段路径对应的 Proto 字段名前缀
+func (g *generator) queryParameters(message *protogen.Message, prefix, protoPrefix string, bound map[string]bool, visiting map[protoreflect.FullName]bool) []knife4g.Parameter {
+	visiting[message.Desc.FullName()] = true
+	defer delete(visiting, message.Desc.FullName())
+
+	var params []knife4g.Parameter
+	for _, field := range message.Fields {
+		protoName := protoPrefix + string(field.Desc.Name())
+		if bound[protoName] || field.Desc.IsMap() {
+			continue
+		}
+		name := prefix + g.fieldName(field)
+		if !isScalarLike(field) {
+			if field.Desc.IsList() || visiting[field.Message.Desc.FullName()] || wellKnownSchema(field.Message.Desc.FullName()) != nil {
+				continue
+			}
+			params = append(params, g.queryParameters(field.Message, name+".", protoName+".", bound, visiting)...)
+			continue
+		}
+		params = append(params, knife4g.Parameter{
+			Name:        name,
+			In:          knife4g.ParamInQuery,
+			Description: commentText(field.Comments.Leading, field.Comments.Trailing),
+			Required:    fieldBehaviors(field.Desc.Options())[fieldBehaviorRequired],
+			Deprecated:  isDeprecated(field.Desc.Options()),
+			Schema:      g.fieldSchema(field),
+		})
+	}
+	return params
+}
+
+// fieldName 返回字段在 JSON 中的名称，naming=proto 时使用 Proto 字段名
+func (g *generator) fieldName(field *protogen.Field) string {
+	if g.options.Naming == namingProto {
+		return string(field.Desc.Name())
+	}
+	return field.Desc.JSONName()
+}
+
+// jsonRequestBody 返回以 application/json 提交的必填请求体
+func jsonRequestBody(schema *knife4g.Schema) *knife4g.RequestBody {
+	return &knife4g.RequestBody{
+		Content:  map[string]knife4g.MediaType{knife4g.MIMEApplicationJSON: {Schema: schema}},
+		Required: true,
+	}
+}
+
+// convertPathTemplate 将 google.api.http 路径模板转换为 OpenAPI 路径，并按出现顺序返回其中的字段路径，
+// 如 "/v1/{name=shelves/*}/books/{book.id}" 转换为 "/v1/{name}/books/{book.id}"
+func convertPathTemplate(template string) (string, []string) {
+	var path strings.Builder
+	var fields []string
+	for {
+		start := strings.IndexByte(template, '{')
+		if start < 0 {
+			path.WriteString(template)
+			return path.String(), fields
+		}
+		end := strings.IndexByte(template[start:], '}')
+		if end < 0 {
+			path.WriteString(template)
+			return path.String(), fields
+		}
+		name, _, _ := strings.Cut(template[start+1:start+end], "=")
+		name = strings.TrimSpace(name)
+		fields = append(fields, name)
+		path.WriteString(template[:start])
+		path.WriteString("{" + name + "}")
+		template = template[start+end+1:]
+	}
+}
+
+// findField 按 Proto 字段名路径（如 "book.id"）查找字段，不存在时返回 nil
+func findField(message *protogen.Message, path string) *protogen.Field {
+	head, rest, nested := strings.Cut(path, ".")
+	for _, field := range message.Fields {
+		if string(field.Desc.Name()) != head {
+			continue
+		}
+		if !nested {
+			return field
+		}
+		if field.Message == nil {
+			return nil
+		}
+		return findField(field.Message, rest)
+	}
+	return nil
+}
+
+// commentText 合并前置注释与行尾注释，去掉每行 "//" 之后的首个空格，保留换行与缩进供 knife4g 解析标注
+func commentText(comments ...protogen.Comments) string {
+	var parts []string
+	for _, comment := range comments {
+		text := strings.TrimSuffix(string(comment), "\n")
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+		lines := strings.Split(text, "\n")
+		for i, line := range lines {
+			lines[i] = strings.TrimPrefix(line, " ")
+		}
+		parts = append(parts, strings.Join(lines, "\n"))
+	}
+	return strings.Join(parts, "\n")
+}
+
+// isDeprecated 判断描述符选项中是否声明了 deprecated = true
+func isDeprecated(options proto.Message) bool {
+	deprecatable, ok := options.(interface{ GetDeprecated() bool })
+	return ok && deprecatable.GetDeprecated()
+}
@@ -0,0 +1,250 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/snac21/knife4g"
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+func TestConvertPathTemplate(t *testing.T) {
+	tests := []struct {
+		template   string
+		wantPath   string
+		wantFields []string
+	}{
+		{"/v1/shelves", "/v1/shelves", nil},
+		{"/v1/shelves/{shelf}", "/v1/shelves/{shelf}", []string{"shelf"}},
+		{"/v1/{name=shelves/*}", "/v1/{name}", []string{"name"}},
+		{"/v1/{name=shelves/*/books/**}:get", "/v1/{name}:get", []string{"name"}},
+		{"/v1/shelves/{shelf}/books/{book.id}", "/v1/shelves/{shelf}/books/{book.id}", []string{"shelf", "book.id"}},
+		{"/v1/{ shelf }/books", "/v1/{shelf}/books", []string{"shelf"}},
+		{"/v1/{shelf", "/v1/{shelf", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.template, func(t *testing.T) {
+			path, fields := convertPathTemplate(tt.template)
+			if path != tt.wantPath || !reflect.DeepEqual(fields, tt.wantFields) {
+				t.Errorf("convertPathTemplate(%q) = %q, %q, want %q, %q", tt.template, path, fields, tt.wantPath, tt.wantFields)
+			}
+		})
+	}
+}
+
+// binding 描述生成的一个接口：参数以 "in:name" 表示，请求体为 $ref 名称或按顺序排列的属性名
+type binding struct {
+	method, path, operationID string
+	params                    []string
+	body                      string
+}
+
+func TestGenerateBindings(t *testing.T) {
+	tests := []struct {
+		name string
+		rule httpRule
+		want []binding
+	}{
+		{
+			name: "no body",
+			rule: httpRule{Method: "get", Path: "/v1/shelves/{shelf}/books"},
+			want: []binding{{
+				method: "get", path: "/v1/shelves/{shelf}/books", operationID: "Bookstore_UpdateBook",
+				params: []string{"path:shelf", "query:book.id", "query:book.title", "query:book.author.name", "query:updateMask"},
+			}},
+		},
+		{
+			name: "body star with path field",
+			rule: httpRule{Method: "post", Path: "/v1/shelves/{shelf}/books", Body: "*"},
+			want: []binding{{
+				method: "post", path: "/v1/shelves/{shelf}/books", operationID: "Bookstore_UpdateBook",
+				params: []string{"path:shelf"},
+				body:   "book,updateMask",
+			}},
+		},
+		{
+			name: "body star without path field",
+			rule: httpRule{Method: "post", Path: "/v1/books:update", Body: "*"},
+			want: []binding{{
+				method: "post", path: "/v1/books:update", operationID: "Bookstore_UpdateBook",
+				body: "$ref UpdateBookRequest",
+			}},
+		},
+		{
+			name: "body field",
+			rule: httpRule{Method: "patch", Path: "/v1/{shelf=shelves/*}/books", Body: "book"},
+			want: []binding{{
+				method: "patch", path: "/v1/{shelf}/books", operationID: "Bookstore_UpdateBook",
+				params: []string{"path:shelf", "query:updateMask"},
+				body:   "$ref Book",
+			}},
+		},
+		{
+			name: "nested path field",
+			rule: httpRule{Method: "get", Path: "/v1/books/{book.id}"},
+			want: []binding{{
+				method: "get", path: "/v1/books/{book.id}", operationID: "Bookstore_UpdateBook",
+				params: []string{"path:book.id", "query:shelf", "query:book.title", "query:book.author.name", "query:updateMask"},
+			}},
+		},
+		{
+			name: "nested path field with body field",
+			rule: httpRule{Method: "put", Path: "/v1/books/{book.id}", Body: "book"},
+			want: []binding{{
+				method: "put", path: "/v1/books/{book.id}", operationID: "Bookstore_UpdateBook",
+				params: []string{"path:book.id", "query:shelf", "query:updateMask"},
+				body:   "$ref Book",
+			}},
+		},
+		{
+			name: "additional bindings",
+			rule: httpRule{
+				Method: "patch", Path: "/v1/shelves/{shelf}/books/{book.id}", Body: "book",
+				Additional: []httpRule{
+					{Method: "post", Path: "/v1/books:update", Body: "*"},
+					{Method: "get", Path: "/v1/books/{book.id}"},
+				},
+			},
+			want: []binding{
+				{
+					method: "patch", path: "/v1/shelves/{shelf}/books/{book.id}", operationID: "Bookstore_UpdateBook",
+					params: []string{"path:shelf", "path:book.id", "query:updateMask"},
+					body:   "$ref Book",
+				},
+				{
+					method: "post", path: "/v1/books:update", operationID: "Bookstore_UpdateBook_1",
+					body: "$ref UpdateBookRequest",
+				},
+				{
+					method: "get", path: "/v1/books/{book.id}", operationID: "Bookstore_UpdateBook_2",
+					params: []string{"path:book.id", "query:shelf", "query:book.title", "query:book.author.name", "query:updateMask"},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := newGenerator(options{Naming: namingJSON}).generate(bookstoreFiles(t, tt.rule))
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := bindings(doc)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got  %+v\nwant %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// bookstoreFiles 构建只含 Bookstore.UpdateBook 方法的 Proto 文件，方法选项为 rule 编码后的 google.api.http
+func bookstoreFiles(t *testing.T, rule httpRule) []*protogen.File {
+	t.Helper()
+	scalar := func(name string, number int32) *descriptorpb.FieldDescriptorProto {
+		return &descriptorpb.FieldDescriptorProto{
+			Name:   proto.String(name),
+			Number: proto.Int32(number),
+			Label:  descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+			Type:   descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+		}
+	}
+	message := func(name string, number int32, typeName string) *descriptorpb.FieldDescriptorProto {
+		return &descriptorpb.FieldDescriptorProto{
+			Name:     proto.String(name),
+			Number:   proto.Int32(number),
+			Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+			Type:     descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum(),
+			TypeName: proto.String(typeName),
+		}
+	}
+
+	methodOptions := &descriptorpb.MethodOptions{}
+	raw := protowire.AppendTag(nil, extHTTPRule, protowire.BytesType)
+	raw = protowire.AppendBytes(raw, encodeHTTPRule(rule))
+	methodOptions.ProtoReflect().SetUnknown(raw)
+
+	file := &descriptorpb.FileDescriptorProto{
+		Name:    proto.String("bookstore.proto"),
+		Package: proto.String("bookstore"),
+		Syntax:  proto.String("proto3"),
+		Options: &descriptorpb.FileOptions{GoPackage: proto.String("example.com/bookstore")},
+		MessageType: []*descriptorpb.DescriptorProto{
+			{Name: proto.String("Author"), Field: []*descriptorpb.FieldDescriptorProto{scalar("name", 1)}},
+			{Name: proto.String("Book"), Field: []*descriptorpb.FieldDescriptorProto{
+				scalar("id", 1), scalar("title", 2), message("author", 3, ".bookstore.Author"),
+			}},
+			{Name: proto.String("UpdateBookRequest"), Field: []*descriptorpb.FieldDescriptorProto{
+				scalar("shelf", 1), message("book", 2, ".bookstore.Book"), scalar("update_mask", 3),
+			}},
+		},
+		Service: []*descriptorpb.ServiceDescriptorProto{{
+			Name: proto.String("Bookstore"),
+			Method: []*descriptorpb.MethodDescriptorProto{{
+				Name:       proto.String("UpdateBook"),
+				InputType:  proto.String(".bookstore.UpdateBookRequest"),
+				OutputType: proto.String(".bookstore.Book"),
+				Options:    methodOptions,
+			}},
+		}},
+	}
+
+	gen, err := protogen.Options{}.New(&pluginpb.CodeGeneratorRequest{
+		FileToGenerate: []string{file.GetName()},
+		ProtoFile:      []*descriptorpb.FileDescriptorProto{file},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return gen.Files
+}
+
+// encodeHTTPRule 按 google.api.HttpRule 的字段号编码 rule，与 parseHTTPRule 互逆
+func encodeHTTPRule(rule httpRule) []byte {
+	patterns := map[string]protowire.Number{"get": 2, "put": 3, "post": 4, "delete": 5, "patch": 6}
+	data := protowire.AppendTag(nil, patterns[rule.Method], protowire.BytesType)
+	data = protowire.AppendString(data, rule.Path)
+	if rule.Body != "" {
+		data = protowire.AppendTag(data, 7, protowire.BytesType)
+		data = protowire.AppendString(data, rule.Body)
+	}
+	for _, additional := range rule.Additional {
+		data = protowire.AppendTag(data, 11, protowire.BytesType)
+		data = protowire.AppendBytes(data, encodeHTTPRule(additional))
+	}
+	return data
+}
+
+// bindings 按路径顺序汇总文档中的接口
+func bindings(doc *knife4g.OpenAPI3) []binding {
+	var result []binding
+	for _, path := range doc.OrderedPaths() {
+		item := doc.Paths[path]
+		for _, entry := range []struct {
+			method string
+			op     *knife4g.Operation
+		}{{"get", item.Get}, {"put", item.Put}, {"post", item.Post}, {"delete", item.Delete}, {"patch", item.Patch}} {
+			if entry.op == nil {
+				continue
+			}
+			b := binding{method: entry.method, path: path, operationID: entry.op.OperationID}
+			for _, param := range entry.op.Parameters {
+				b.params = append(b.params, param.In+":"+param.Name)
+			}
+			if entry.op.RequestBody != nil {
+				schema := entry.op.RequestBody.Content[knife4g.MIMEApplicationJSON].Schema
+				if schema.Ref != "" {
+					b.body = "$ref " + strings.TrimPrefix(schema.Ref, componentsSchemasPrefix)
+				} else {
+					b.body = strings.Join(schema.OrderedProperties(), ",")
+				}
+			}
+			result = append(result, b)
+		}
+	}
+	return result
+}
@@ -0,0 +1,13 @@
+module github.com/snac21/knife4g/cmd/protoc-gen-knife4g
+
+go 1.25.11
+
+require (
+	github.com/snac21/knife4g v0.0.0-20261018140115-62086c4dda21
+	google.golang.org/protobuf v1.36.12
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require github.com/kr/text v0.2.0 // indirect
+
+replace github.com/snac21/knife4g => ../..
@@ -0,0 +1,64 @@
+// protoc-gen-knife4g 是 protoc 插件，读取 Service 上的 google.api.http 选项与 Proto 注释，
+// 生成可直接交给 knife4g.Config.OpenAPI 使用的 OpenAPI 3.0 文档。
+//
+// 用法：
+//
+//	protoc -I . -I third_party --knife4g_out=. --knife4g_opt=title=Bookstore,version=1.0.0 bookstore.proto
+//
+// 参数：
+//
+//	output   输出文件名，默认 openapi.yaml
+//	title    info.title，默认为唯一的 Service 名称
+//	version  info.version，默认 0.0.1
+//	naming   字段命名方式：json（默认，与 protojson 一致）或 proto
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/types/pluginpb"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	namingJSON  = "json"
+	namingProto = "proto"
+)
+
+// options 插件参数，由 --knife4g_opt 传入
+type options struct {
+	Output  string
+	Title   string
+	Version string
+	Naming  string
+}
+
+func main() {
+	var opts options
+	var flags flag.FlagSet
+	flags.StringVar(&opts.Output, "output", "openapi.yaml", "output file name")
+	flags.StringVar(&opts.Title, "title", "", "info.title of the document")
+	flags.StringVar(&opts.Version, "version", "0.0.1", "info.version of the document")
+	flags.StringVar(&opts.Naming, "naming", namingJSON, `field naming: "json" or "proto"`)
+
+	protogen.Options{ParamFunc: flags.Set}.Run(func(gen *protogen.Plugin) error {
+		gen.SupportedFeatures = uint64(pluginpb.CodeGeneratorResponse_FEATURE_PROTO3_OPTIONAL)
+		if opts.Naming != namingJSON && opts.Naming != namingProto {
+			return fmt.Errorf("invalid naming %q, expected %q or %q", opts.Naming, namingJSON, namingProto)
+		}
+
+		doc, err := newGenerator(opts).generate(gen.Files)
+		if err != nil {
+			return err
+		}
+		data, err := yaml.Marshal(doc)
+		if err != nil {
+			return err
+		}
+		out := gen.NewGeneratedFile(opts.Output, "")
+		_, err = out.Write(data)
+		return err
+	})
+}
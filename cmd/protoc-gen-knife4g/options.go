@@ -0,0 +1,206 @@
+package main
+
+import (
+	"errors"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+)
+
+// google/api/annotations.proto 与 google/api/field_behavior.proto 中的扩展字段号。
+// 直接按字段号解码选项的原始字节，无需依赖 genproto
+const (
+	extHTTPRule      protowire.Number = 72295728 // google.api.http，位于 MethodOptions
+	extFieldBehavior protowire.Number = 1052     // google.api.field_behavior，位于 FieldOptions
+)
+
+// google.api.FieldBehavior 中本插件使用的取值
+const (
+	fieldBehaviorRequired   = 2
+	fieldBehaviorOutputOnly = 3
+	fieldBehaviorInputOnly  = 4
+)
+
+// httpRule 对应 google.api.HttpRule
+type httpRule struct {
+	Method       string // 小写的 HTTP 方法
+	Path         string // 原始路径模板，如 "/v1/{name=shelves/*}"
+	Body         string // "*"、字段名或空
+	ResponseBody string
+	Additional   []httpRule
+}
+
+// rawField 表示一个未经解码的字段值
+type rawField struct {
+	Type  protowire.Type
+	Value []byte // 不含 tag 的字段值，BytesType 时含长度前缀
+}
+
+// extensionValues 返回选项消息中指定扩展字段的全部原始值，扩展未注册时同样可以读取
+func extensionValues(options proto.Message, number protowire.Number) ([]rawField, error) {
+	if options == nil {
+		return nil, nil
+	}
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(options)
+	if err != nil {
+		return nil, err
+	}
+	var values []rawField
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return nil, protowire.ParseError(n)
+		}
+		data = data[n:]
+		m := protowire.ConsumeFieldValue(num, typ, data)
+		if m < 0 {
+			return nil, protowire.ParseError(m)
+		}
+		if num == number {
+			values = append(values, rawField{Type: typ, Value: data[:m]})
+		}
+		data = data[m:]
+	}
+	return values, nil
+}
+
+// methodHTTPRule 读取方法上的 google.api.http 选项，未声明时返回 nil
+func methodHTTPRule(options proto.Message) (*httpRule, error) {
+	values, err := extensionValues(options, extHTTPRule)
+	if err != nil || len(values) == 0 {
+		return nil, err
+	}
+	last := values[len(values)-1]
+	if last.Type != protowire.BytesType {
+		return nil, errors.New("google.api.http is not a message")
+	}
+	raw, n := protowire.ConsumeBytes(last.Value)
+	if n < 0 {
+		return nil, protowire.ParseError(n)
+	}
+	rule, err := parseHTTPRule(raw)
+	if err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// parseHTTPRule 解码 google.api.HttpRule 消息
+func parseHTTPRule(data []byte) (httpRule, error) {
+	var rule httpRule
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return rule, protowire.ParseError(n)
+		}
+		data = data[n:]
+		if typ != protowire.BytesType {
+			m := protowire.ConsumeFieldValue(num, typ, data)
+			if m < 0 {
+				return rule, protowire.ParseError(m)
+			}
+			data = data[m:]
+			continue
+		}
+		value, m := protowire.ConsumeBytes(data)
+		if m < 0 {
+			return rule, protowire.ParseError(m)
+		}
+		data = data[m:]
+
+		switch num {
+		case 2:
+			rule.Method, rule.Path = "get", string(value)
+		case 3:
+			rule.Method, rule.Path = "put", string(value)
+		case 4:
+			rule.Method, rule.Path = "post", string(value)
+		case 5:
+			rule.Method, rule.Path = "delete", string(value)
+		case 6:
+			rule.Method, rule.Path = "patch", string(value)
+		case 7:
+			rule.Body = string(value)
+		case 8:
+			kind, path, err := parseCustomHTTPPattern(value)
+			if err != nil {
+				return rule, err
+			}
+			rule.Method, rule.Path = strings.ToLower(kind), path
+		case 11:
+			additional, err := parseHTTPRule(value)
+			if err != nil {
+				return rule, err
+			}
+			rule.Additional = append(rule.Additional, additional)
+		case 12:
+			rule.ResponseBody = string(value)
+		}
+	}
+	if rule.Path == "" && len(rule.Additional) == 0 {
+		return rule, errors.New("google.api.http has no pattern")
+	}
+	return rule, nil
+}
+
+// parseCustomHTTPPattern 解码 google.api.CustomHttpPattern 消息
+func parseCustomHTTPPattern(data []byte) (kind, path string, err error) {
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return "", "", protowire.ParseError(n)
+		}
+		data = data[n:]
+		if typ != protowire.BytesType {
+			m := protowire.ConsumeFieldValue(num, typ, data)
+			if m < 0 {
+				return "", "", protowire.ParseError(m)
+			}
+			data = data[m:]
+			continue
+		}
+		value, m := protowire.ConsumeBytes(data)
+		if m < 0 {
+			return "", "", protowire.ParseError(m)
+		}
+		data = data[m:]
+		switch num {
+		case 1:
+			kind = string(value)
+		case 2:
+			path = string(value)
+		}
+	}
+	return kind, path, nil
+}
+
+// fieldBehaviors 读取字段上的 google.api.field_behavior 选项，兼容 packed 与非 packed 编码
+func fieldBehaviors(options proto.Message) map[uint64]bool {
+	values, err := extensionValues(options, extFieldBehavior)
+	if err != nil {
+		return nil
+	}
+	result := make(map[uint64]bool)
+	for _, value := range values {
+		if value.Type == protowire.VarintType {
+			if v, n := protowire.ConsumeVarint(value.Value); n > 0 {
+				result[v] = true
+			}
+			continue
+		}
+		packed, n := protowire.ConsumeBytes(value.Value)
+		if n < 0 {
+			continue
+		}
+		for len(packed) > 0 {
+			v, m := protowire.ConsumeVarint(packed)
+			if m < 0 {
+				break
+			}
+			result[v] = true
+			packed = packed[m:]
+		}
+	}
+	return result
+}
@@ -0,0 +1,225 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/snac21/knife4g"
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const componentsSchemasPrefix = "#/components/schemas/"
+
+// scalarSchema 返回 Proto 标量类型对应的 Schema，按 protojson 的编码规则：64 位整数编码为字符串
+func scalarSchema(kind protoreflect.Kind) *knife4g.Schema {
+	switch kind {
+	case protoreflect.BoolKind:
+		return &knife4g.Schema{Type: knife4g.SchemaType{"boolean"}}
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return &knife4g.Schema{Type: knife4g.SchemaType{"integer"}, Format: "int32"}
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return &knife4g.Schema{Type: knife4g.SchemaType{"integer"}, Format: "uint32"}
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return &knife4g.Schema{Type: knife4g.SchemaType{"string"}, Format: "int64"}
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return &knife4g.Schema{Type: knife4g.SchemaType{"string"}, Format: "uint64"}
+	case protoreflect.FloatKind:
+		return &knife4g.Schema{Type: knife4g.SchemaType{"number"}, Format: "float"}
+	case protoreflect.DoubleKind:
+		return &knife4g.Schema{Type: knife4g.SchemaType{"number"}, Format: "double"}
+	case protoreflect.BytesKind:
+		return &knife4g.Schema{Type: knife4g.SchemaType{"string"}, Format: "byte"}
+	default:
+		return &knife4g.Schema{Type: knife4g.SchemaType{"string"}}
+	}
+}
+
+// wellKnownSchema 返回 google.protobuf 常用类型在 protojson 中的表示，非此类类型时返回 nil
+func wellKnownSchema(name protoreflect.FullName) *knife4g.Schema {
+	switch name {
+	case "google.protobuf.Timestamp":
+		return &knife4g.Schema{Type: knife4g.SchemaType{"string"}, Format: "date-time"}
+	case "google.protobuf.Duration":
+		return &knife4g.Schema{Type: knife4g.SchemaType{"string"}, Pattern: `^-?[0-9]+(\.[0-9]+)?s$`}
+	case "google.protobuf.FieldMask":
+		return &knife4g.Schema{Type: knife4g.SchemaType{"string"}, Format: "field-mask"}
+	case "google.protobuf.Empty":
+		return &knife4g.Schema{Type: knife4g.SchemaType{"object"}}
+	case "google.protobuf.Struct":
+		return &knife4g.Schema{Type: knife4g.SchemaType{"object"}, AdditionalProperties: &knife4g.SchemaOrBool{Allows: true, IsBool: true}}
+	case "google.protobuf.Value":
+		return &knife4g.Schema{}
+	case "google.protobuf.ListValue":
+		return &knife4g.Schema{Type: knife4g.SchemaType{"array"}, Items: &knife4g.Schema{}}
+	case "google.protobuf.BoolValue":
+		return scalarSchema(protoreflect.BoolKind)
+	case "google.protobuf.Int32Value":
+		return scalarSchema(protoreflect.Int32Kind)
+	case "google.protobuf.UInt32Value":
+		return scalarSchema(protoreflect.Uint32Kind)
+	case "google.protobuf.Int64Value":
+		return scalarSchema(protoreflect.Int64Kind)
+	case "google.protobuf.UInt64Value":
+		return scalarSchema(protoreflect.Uint64Kind)
+	case "google.protobuf.FloatValue":
+		return scalarSchema(protoreflect.FloatKind)
+	case "google.protobuf.DoubleValue":
+		return scalarSchema(protoreflect.DoubleKind)
+	case "google.protobuf.StringValue":
+		return scalarSchema(protoreflect.StringKind)
+	case "google.protobuf.BytesValue":
+		return scalarSchema(protoreflect.BytesKind)
+	}
+	return nil
+}
+
+// isScalarLike 判断字段能否作为 path/query 参数：标量、枚举以及编码为标量的常用类型
+func isScalarLike(field *protogen.Field) bool {
+	if field.Desc.Kind() != protoreflect.MessageKind {
+		return true
+	}
+	schema := wellKnownSchema(field.Message.Desc.FullName())
+	return schema != nil && len(schema.Type) > 0 && !schema.Type.Is("object") && !schema.Type.Is("array")
+}
+
+// enumSchema 返回枚举字段的 Schema：按 protojson 输出为值名称，各值的注释写入 x-enum-descriptions
+func enumSchema(enum *protogen.Enum) *knife4g.Schema {
+	schema := &knife4g.Schema{Type: knife4g.SchemaType{"string"}}
+	descriptions := make([]any, len(enum.Values))
+	described := false
+	for i, value := range enum.Values {
+		schema.Enum = append(schema.Enum, string(value.Desc.Name()))
+		text := strings.Join(strings.Fields(commentText(value.Comments.Leading, value.Comments.Trailing)), " ")
+		descriptions[i] = text
+		described = described || text != ""
+	}
+	if described {
+		schema.Extensions.Set(knife4g.ExtEnumDescriptions, descriptions)
+	}
+	schema.Deprecated = isDeprecated(enum.Desc.Options())
+	return schema
+}
+
+// fieldSchema 返回字段的 Schema，消息类型以 $ref 引用 components.schemas
+func (g *generator) fieldSchema(field *protogen.Field) *knife4g.Schema {
+	if field.Desc.IsMap() {
+		value := field.Message.Fields[1]
+		return &knife4g.Schema{
+			Type:                 knife4g.SchemaType{"object"},
+			AdditionalProperties: &knife4g.SchemaOrBool{Schema: g.singularSchema(value)},
+		}
+	}
+	schema := g.singularSchema(field)
+	if field.Desc.IsList() {
+		return &knife4g.Schema{Type: knife4g.SchemaType{"array"}, Items: schema}
+	}
+	return schema
+}
+
+// singularSchema 返回字段单个取值的 Schema，不考虑 repeated
+func (g *generator) singularSchema(field *protogen.Field) *knife4g.Schema {
+	switch field.Desc.Kind() {
+	case protoreflect.EnumKind:
+		return enumSchema(field.Enum)
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return g.messageSchema(field.Message)
+	default:
+		return scalarSchema(field.Desc.Kind())
+	}
+}
+
+// messageSchema 返回消息类型的引用，首次引用时在 components.schemas 中生成其定义
+func (g *generator) messageSchema(message *protogen.Message) *knife4g.Schema {
+	if schema := wellKnownSchema(message.Desc.FullName()); schema != nil {
+		return schema
+	}
+	if message.Desc.FullName() == "google.protobuf.Any" {
+		return g.anySchema()
+	}
+	// 嵌套消息以 "_" 连接外层名称
+	name := strings.TrimPrefix(string(message.Desc.FullName()), string(message.Desc.ParentFile().Package())+".")
+	return g.componentSchema(message.Desc.FullName(), strings.ReplaceAll(name, ".", "_"), func() *knife4g.Schema {
+		return g.objectSchema(message, nil)
+	})
+}
+
+// componentSchema 返回指向 components.schemas 的引用，fullName 首次出现时以 build 生成其定义。
+// 先占位再生成，保证定义按首次引用的顺序排列，并避免循环引用导致的无限递归；
+// name 已被其他类型占用时改用带包名的全名
+func (g *generator) componentSchema(fullName protoreflect.FullName, name string, build func() *knife4g.Schema) *knife4g.Schema {
+	if existing, ok := g.schemaNames[fullName]; ok {
+		return &knife4g.Schema{Ref: componentsSchemasPrefix + existing}
+	}
+	if _, taken := g.doc.Components.Schemas[name]; taken {
+		name = strings.ReplaceAll(string(fullName), ".", "_")
+	}
+	g.schemaNames[fullName] = name
+	g.doc.Components.SetSchema(name, knife4g.Schema{})
+	g.doc.Components.SetSchema(name, *build())
+	return &knife4g.Schema{Ref: componentsSchemasPrefix + name}
+}
+
+// objectSchema 生成消息的对象 Schema，字段按声明顺序输出，exclude 中的字段被跳过
+func (g *generator) objectSchema(message *protogen.Message, exclude map[string]bool) *knife4g.Schema {
+	schema := &knife4g.Schema{
+		Type:        knife4g.SchemaType{"object"},
+		Description: commentText(message.Comments.Leading, message.Comments.Trailing),
+		Deprecated:  isDeprecated(message.Desc.Options()),
+	}
+	for _, field := range message.Fields {
+		if exclude[string(field.Desc.Name())] {
+			continue
+		}
+		property := g.fieldSchema(field)
+		if description := commentText(field.Comments.Leading, field.Comments.Trailing); description != "" {
+			property.Description = description
+		}
+		if isDeprecated(field.Desc.Options()) {
+			property.Deprecated = true
+		}
+		behaviors := fieldBehaviors(field.Desc.Options())
+		property.ReadOnly = behaviors[fieldBehaviorOutputOnly]
+		property.WriteOnly = behaviors[fieldBehaviorInputOnly]
+		if field.Oneof != nil && !field.Oneof.Desc.IsSynthetic() {
+			property.Extensions.Set(knife4g.ExtOneOf, string(field.Oneof.Desc.Name()))
+		}
+
+		name := g.fieldName(field)
+		if behaviors[fieldBehaviorRequired] {
+			schema.Required = append(schema.Required, name)
+		}
+		schema.SetProperty(name, property)
+	}
+	return schema
+}
+
+// anySchema 返回 google.protobuf.Any 的引用，其 protojson 表示为带 @type 的对象
+func (g *generator) anySchema() *knife4g.Schema {
+	return g.componentSchema("google.protobuf.Any", "GoogleProtobufAny", func() *knife4g.Schema {
+		schema := &knife4g.Schema{
+			Type:                 knife4g.SchemaType{"object"},
+			Description:          "Contains an arbitrary serialized message along with a @type that describes the type of the serialized message.",
+			AdditionalProperties: &knife4g.SchemaOrBool{Allows: true, IsBool: true},
+		}
+		schema.SetProperty("@type", &knife4g.Schema{Type: knife4g.SchemaType{"string"}, Description: "The type of the serialized message."})
+		return schema
+	})
+}
+
+// statusSchema 返回 google.rpc.Status 的引用，用作各接口的 default 响应
+func (g *generator) statusSchema() *knife4g.Schema {
+	return g.componentSchema("google.rpc.Status", "Status", func() *knife4g.Schema {
+		schema := &knife4g.Schema{
+			Type:        knife4g.SchemaType{"object"},
+			Description: "The `Status` type defines a logical error model that is suitable for different programming environments, including REST APIs and RPC APIs.",
+		}
+		schema.SetProperty("code", &knife4g.Schema{Type: knife4g.SchemaType{"integer"}, Format: "int32", Description: "The status code, which should be an enum value of google.rpc.Code."})
+		schema.SetProperty("message", &knife4g.Schema{Type: knife4g.SchemaType{"string"}, Description: "A developer-facing error message."})
+		schema.SetProperty("details", &knife4g.Schema{
+			Type:        knife4g.SchemaType{"array"},
+			Items:       g.anySchema(),
+			Description: "A list of messages that carry the error details.",
+		})
+		return schema
+	})
+}
@@ -1,17 +1,58 @@
 package knife4g
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 )
 
 // CommentParser 注释解析器，用于解析 Proto 注释以及 OpenAPI 描述信息中的自定义标注扩展指令（如 @tags, @summary, @description 等）
 type CommentParser struct {
-	tags         map[string]string   // 存储字符串类型的单值标签映射（如 key="tags", value="基础服务"）
-	arrayTags    map[string][]string // 存储切片/数组类型的多值标签映射（如 key="tags", value=["基础服务", "通用服务"]）
-	numberTags   map[string]float64  // 存储数值类型的标签映射（如 key="minLength", value=1）
-	boolTags     map[string]bool     // 存储布尔类型的标签映射（如 key="required", value=true）
-	responseTags map[string]string   // 存储 HTTP 响应状态码与响应类型的映射（如 key="400", value="ErrorResponse"）
+	tags         map[string]string    // 存储字符串类型的单值标签映射（如 key="tags", value="基础服务"）
+	arrayTags    map[string][]string  // 存储切片/数组类型的多值标签映射（如 key="tags", value=["基础服务", "通用服务"]）
+	numberTags   map[string]float64   // 存储数值类型的标签映射（如 key="minLength", value=1）
+	boolTags     map[string]bool      // 存储布尔类型的标签映射（如 key="required", value=true）
+	responseTags map[string]string    // 存储 HTTP 响应状态码与响应类型的映射（如 key="400", value="ErrorResponse"）
+	responses    []ResponseAnnotation // 按声明顺序存储完整的 @response 标注
+	examples     []ExampleAnnotation  // 按声明顺序存储 @example 与 @example[name] 示例
+	custom       []customAnnotation   // 按声明顺序存储通过 RegisterAnnotation 注册的自定义标注
+	enumValues   []EnumValue          // @enum 标注中的枚举值及其名称、说明
+	parameters   []Parameter          // 按声明顺序存储 @header、@cookie 与 @query 声明的参数
+	headers      []ResponseHeaderAnnotation
+	routes       []RouteAnnotation // 按声明顺序存储 @router 标注
+	diagnostics  []AnnotationDiagnostic
+}
+
+// ExampleAnnotation 表示一条 @example 标注，Name 为空表示默认示例
+type ExampleAnnotation struct {
+	Name  string
+	Value any    // 按 JSON 解析后的值，无法解析时为原始字符串
+	Raw   string // 去除包裹双引号后的原始文本
+}
+
+// ResponseAnnotation 表示一条 @response 标注，格式为 "状态码: 类型名 | 描述 | 媒体类型"，描述与媒体类型可省略
+type ResponseAnnotation struct {
+	Code        string // HTTP 状态码，支持 "400"、"4XX" 通配与 "default"
+	Schema      string // 引用的 components.schemas 名称，为空表示无响应体
+	Description string
+	ContentType string
+}
+
+// ResponseHeaderAnnotation 表示一条 @responseHeader 标注，格式为 "状态码 名称 [类型] [required] ["描述"] [example=值]"
+type ResponseHeaderAnnotation struct {
+	Code   string // HTTP 状态码，写法同 @response
+	Name   string
+	Header Header
+}
+
+// RouteAnnotation 表示一条 @router 标注，格式为 "路径 [方法]"，如 "/users/{id} [get]"
+type RouteAnnotation struct {
+	Path   string
+	Method string // 小写的 HTTP 方法
 }
 
 // NewCommentParser 创建并初始化一个新的注释解析器实例
@@ -25,96 +66,310 @@ func NewCommentParser() *CommentParser {
 	}
 }
 
-// Parse 解析输入的多行注释字符串，按行提取包含 @ 前缀的标签及其参数
+// Parse 解析输入的多行注释字符串，按行提取包含 @ 前缀的标签及其参数。
+// 非标注文本按 Markdown 保留（包括空行、列表缩进与 ``` 代码块），作为默认的 description；
+// @description: 之后直到下一个标注前的文本都属于该描述；其他字符串标签可通过更深的缩进续行
 func (p *CommentParser) Parse(comment string) *CommentParser {
 	if comment == "" {
 		return p
 	}
 
+	var (
+		text          []string // 普通描述文本
+		descLines     []string // @description: 块
+		inDescription bool
+		inFence       bool
+		contTag       string // 可续行的字符串标签
+		contIndent    int
+	)
+
 	// 按换行符分割多行文本
 	lines := strings.Split(comment, "\n")
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if line == "" {
+	for i := 0; i < len(lines); i++ {
+		raw := strings.TrimRight(lines[i], " \t\r")
+		line := strings.TrimSpace(raw)
+
+		// ``` 代码块内的内容原样保留，不识别其中的标注
+		if inFence || strings.HasPrefix(line, "```") {
+			if strings.HasPrefix(line, "```") {
+				inFence = !inFence
+			}
+			if inDescription {
+				descLines = append(descLines, raw)
+			} else {
+				text = append(text, raw)
+			}
+			contTag = ""
 			continue
 		}
 
-		// 处理以 @ 开头的标签格式，如 "@tags: 基础服务"
-		if strings.HasPrefix(line, "@") {
-			parts := strings.SplitN(line[1:], ":", 2)
-			if len(parts) != 2 {
+		// 处理以 @ 开头的标签格式，如 "@tags: 基础服务"；仅含 @ 的普通文本（如邮箱、装饰器）按描述处理
+		tag, value, isTag := splitAnnotation(line)
+		if !isTag && flagAnnotations[strings.TrimPrefix(line, "@")] {
+			// @deprecated、@hidden、@internal 可省略冒号
+			tag, value, isTag = strings.TrimPrefix(line, "@"), "", true
+		}
+		if !isTag {
+			if name := strings.Fields(strings.TrimPrefix(line, "@")); strings.HasPrefix(line, "@") && len(name) > 0 && isKnownAnnotation(name[0]) {
+				p.addDiagnostic(name[0], line, "missing ':' after @%s, the line is kept as description text", name[0])
+			}
+			if contTag != "" && line != "" && indentOf(raw) > contIndent {
+				p.tags[contTag] += " " + line
 				continue
 			}
+			contTag = ""
+			if inDescription {
+				descLines = append(descLines, raw)
+			} else {
+				text = append(text, raw)
+			}
+			continue
+		}
+		inDescription = false
+		contTag = ""
+
+		// @example[name] 命名示例
+		exampleName := ""
+		if strings.HasPrefix(tag, "example[") && strings.HasSuffix(tag, "]") {
+			exampleName = strings.TrimSpace(tag[len("example[") : len(tag)-1])
+			tag = "example"
+		}
+		// 以 ``` 开头的示例值读取到下一个 ``` 为止，支持多行 JSON
+		if tag == "example" && strings.HasPrefix(value, "```") {
+			var block []string
+			for i++; i < len(lines) && strings.TrimSpace(lines[i]) != "```"; i++ {
+				block = append(block, lines[i])
+			}
+			if i >= len(lines) {
+				p.addDiagnostic(tag, value, "unterminated ``` block")
+			}
+			value = dedent(block)
+		}
+
+		// 根据不同的标签名称进行分类
+		switch tag {
+		case TagConsumes, TagProduces:
+			// 媒体类型列表，按逗号分隔，第一项为默认类型
+			p.boolTags[tag] = true
+			p.tags[tag] = value
+			p.arrayTags[tag] = splitList(value)
+
+		case "description":
+			// 描述块，后续非标注行均作为描述续行
+			inDescription = true
+			descLines = []string{value}
+
+		case "tags":
+			// 处理标签列表，支持单个标签以及按逗号分隔的多标签切片
+			value = strings.TrimSpace(value)
+			p.tags[tag] = value
+			values := strings.Split(value, ",")
+			for i, v := range values {
+				values[i] = strings.TrimSpace(v)
+			}
+			p.arrayTags[tag] = values
 
-			tag := strings.TrimSpace(parts[0])
-			value := strings.TrimSpace(parts[1])
+		case "oneOf", "anyOf":
+			// 组合的 Schema 名称列表，如 "CardPayment, BankPayment"
+			p.tags[tag] = value
+			p.arrayTags[tag] = splitList(value)
 
-			// 根据不同的标签名称进行分类
-			switch tag {
-			case "consumes":
+		case "discriminator":
+			// 多态判别属性，可追加 "| 取值=类型名, ..." 映射
+			p.tags[tag] = value
+			if _, _, err := parseDiscriminatorAnnotation(value); err != nil {
+				p.addDiagnostic(tag, value, "%v", err)
+			}
+
+		case "enum":
+			// 处理枚举值列表，支持 "[1,2,3]" 与带名称、说明的 "1=Pending: 待支付, 2=Paid"
+			p.enumValues = parseEnumAnnotation(value)
+			values := make([]string, len(p.enumValues))
+			for i, v := range p.enumValues {
+				values[i] = v.Value
+			}
+			p.arrayTags[tag] = values
+
+		case "minLength", "maxLength", "minimum", "maximum", "multipleOf", "minItems", "maxItems", "order":
+			// 处理数值类型的校验约束与 @order 排序值
+			if num, err := strconv.ParseFloat(value, 64); err == nil {
+				p.numberTags[tag] = num
+			} else {
+				p.addDiagnostic(tag, value, "invalid number")
+			}
+
+		case TagDeprecated, TagHidden, TagInternal:
+			// 弃用与可见性标记，值为 true/false 或说明文字（如 "use /v2/orders"）
+			if flag, err := strconv.ParseBool(value); err == nil {
+				p.boolTags[tag] = flag
+			} else {
 				p.boolTags[tag] = true
 				p.tags[tag] = value
+			}
 
-			case "tags":
-				// 处理标签列表，支持单个标签以及按逗号分隔的多标签切片
-				value = strings.TrimSpace(value)
-				p.tags[tag] = value
-				values := strings.Split(value, ",")
-				for i, v := range values {
-					values[i] = strings.TrimSpace(v)
-				}
-				p.arrayTags[tag] = values
-
-			case "enum":
-				// 处理枚举值列表，去除方括号并按逗号分割
-				value = strings.Trim(value, "[]")
-				values := strings.Split(value, ",")
-				for i, v := range values {
-					values[i] = strings.TrimSpace(v)
-				}
-				p.arrayTags[tag] = values
+		case "nullable", "readOnly", "writeOnly", "uniqueItems":
+			// 布尔类型的 Schema 属性，空值视为 true
+			p.parseBoolTag(tag, value)
 
-			case "minLength", "maxLength", "minimum", "maximum":
-				// 处理数值类型的校验约束
-				if num, err := strconv.ParseFloat(value, 64); err == nil {
-					p.numberTags[tag] = num
-				}
+		case "exclusiveMinimum", "exclusiveMaximum":
+			// 排他边界：布尔值为 3.0 写法，数值为 3.1 写法
+			if num, err := strconv.ParseFloat(value, 64); err == nil {
+				p.numberTags[tag] = num
+			} else {
+				p.parseBoolTag(tag, value)
+			}
 
-			case "required":
-				// 处理布尔类型的必填状态
-				p.boolTags[tag] = value == "true"
+		case "required":
+			// 处理布尔类型的必填状态，空值视为 true
+			p.parseBoolTag(tag, value)
 
-			case "example":
-				// 处理示例值，去除包裹的双引号
-				value = strings.Trim(value, "\"")
-				p.tags[tag] = value
+		case "example":
+			// 处理示例值：按 JSON 解析出数值、布尔、对象与数组，字符串形式去除包裹的双引号
+			example := ExampleAnnotation{Name: exampleName, Value: parseExampleValue(value), Raw: strings.Trim(value, "\"")}
+			p.examples = append(p.examples, example)
+			if exampleName == "" {
+				p.tags[tag] = example.Raw
+			}
 
-			case "response":
-				// 处理响应标签，标准格式如 "400: ErrorResponse"
-				if strings.Contains(value, ":") {
-					responseParts := strings.SplitN(value, ":", 2)
-					if len(responseParts) == 2 {
-						code := strings.TrimSpace(responseParts[0])
-						responseType := strings.TrimSpace(responseParts[1])
-						p.responseTags[code] = responseType
-					}
-				}
+		case "response":
+			// 处理响应标签，标准格式如 "400: ErrorResponse"，可追加 "| 描述 | 媒体类型"
+			if annotation, ok := parseResponseAnnotation(value); ok {
+				p.responseTags[annotation.Code] = annotation.Schema
+				p.responses = append(p.responses, annotation)
+			} else {
+				p.addDiagnostic(tag, value, `expected "<code>: <Schema>" with a status code, "4XX" wildcard or "default"`)
+			}
+
+		case "responseHeader":
+			// 响应头，格式如 `201 Location string "新建资源的地址"`
+			if annotation, err := parseResponseHeaderAnnotation(value); err == nil {
+				p.headers = append(p.headers, annotation)
+			} else {
+				p.addDiagnostic(tag, value, "%v", err)
+			}
 
-			default:
-				// 其他未特殊处理的标签，统一作为字符串类型存储
+		case ParamInHeader, ParamInCookie, ParamInQuery:
+			// 接口参数，格式如 `X-Tenant-Id string required "租户 ID" example=acme`
+			if param, err := parseParameterAnnotation(tag, value); err == nil {
+				p.parameters = append(p.parameters, param)
+			} else {
+				p.addDiagnostic(tag, value, "%v", err)
+			}
+
+		case TagParam:
+			// 带位置的接口参数，格式如 `id path integer "用户 ID"`，path 参数总是必填
+			if param, err := parseParamAnnotation(value); err == nil {
+				p.parameters = append(p.parameters, param)
+			} else {
+				p.addDiagnostic(tag, value, "%v", err)
+			}
+
+		case TagRouter:
+			// 接口路由，格式如 `/users/{id} [get]`，由 cmd/knife4g scan 读取
+			if route, err := parseRouteAnnotation(value); err == nil {
 				p.tags[tag] = value
+				p.routes = append(p.routes, route)
+			} else {
+				p.addDiagnostic(tag, value, "%v", err)
 			}
-		} else {
-			// 处理无 @ 前缀的普通描述文本，作为默认的 description
-			if _, exists := p.tags["description"]; !exists {
-				p.tags["description"] = line
+
+		default:
+			// 其他未特殊处理的标签，统一作为字符串类型存储，缩进更深的后续行作为续行
+			p.tags[tag] = value
+			contTag, contIndent = tag, indentOf(raw)
+			if _, registered := LookupAnnotation(tag); registered {
+				p.custom = append(p.custom, customAnnotation{name: tag})
+			} else if !builtinAnnotations[tag] {
+				if suggestion := suggestAnnotation(tag); suggestion != "" {
+					p.addDiagnostic(tag, value, "unknown annotation, did you mean @%s?", suggestion)
+				} else {
+					p.addDiagnostic(tag, value, "unknown annotation")
+				}
 			}
 		}
 	}
 
+	// 自定义标注在续行合并完成后按注册的值类型解析
+	p.resolveCustomAnnotations()
+
+	// 显式的 @description: 优先于普通描述文本
+	if descLines != nil {
+		p.tags["description"] = trimBlankLines(descLines[0] + "\n" + dedent(descLines[1:]))
+	} else if description := trimBlankLines(dedent(text)); description != "" {
+		p.tags["description"] = description
+	}
+
 	return p
 }
 
+// resolveCustomAnnotations 按注册的值类型解析自定义标注，数值、布尔与列表同时写入对应的取值映射
+func (p *CommentParser) resolveCustomAnnotations() {
+	resolved := p.custom[:0]
+	seen := make(map[string]bool, len(p.custom))
+	for _, custom := range p.custom {
+		if seen[custom.name] {
+			continue
+		}
+		seen[custom.name] = true
+		annotation, _ := LookupAnnotation(custom.name)
+		raw := p.tags[custom.name]
+		value, err := parseAnnotationValue(annotation.ValueType, raw)
+		if err != nil {
+			p.addDiagnostic(custom.name, raw, "invalid value: %v", err)
+			continue
+		}
+		switch v := value.(type) {
+		case float64:
+			p.numberTags[custom.name] = v
+		case bool:
+			p.boolTags[custom.name] = v
+		case []string:
+			p.arrayTags[custom.name] = v
+		}
+		resolved = append(resolved, customAnnotation{name: custom.name, annotation: annotation, raw: raw, value: value})
+	}
+	p.custom = resolved
+}
+
+// flagAnnotations 可不带冒号与取值单独成行的标注
+var flagAnnotations = map[string]bool{
+	TagDeprecated: true, TagHidden: true, TagInternal: true,
+	"nullable": true, "readOnly": true, "writeOnly": true, "uniqueItems": true,
+}
+
+// annotationPattern 匹配 "@name: value" 形式的标注行，name 可带 [key] 后缀
+var annotationPattern = regexp.MustCompile(`^@([A-Za-z][A-Za-z0-9_.-]*(?:\[[^\]]*\])?)\s*:(.*)$`)
+
+// splitAnnotation 拆分标注行为标签名与值，不符合标注格式时返回 false
+func splitAnnotation(line string) (tag, value string, ok bool) {
+	match := annotationPattern.FindStringSubmatch(line)
+	if match == nil {
+		return "", "", false
+	}
+	return match[1], strings.TrimSpace(match[2]), true
+}
+
+// splitList 按逗号拆分列表并去除空白与空项
+func splitList(value string) []string {
+	var result []string
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// indentOf 返回行首空白字符数
+func indentOf(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " \t"))
+}
+
+// trimBlankLines 去除首尾空行
+func trimBlankLines(text string) string {
+	return strings.Trim(text, "\n")
+}
+
 // GetString 获取指定标签名称对应的字符串类型值
 func (p *CommentParser) GetString(tag string) string {
 	return p.tags[tag]
@@ -145,6 +400,83 @@ func (p *CommentParser) GetResponses() map[string]string {
 	return p.responseTags
 }
 
+// GetEnumValues 获取 @enum 标注中的枚举值及其名称与说明
+func (p *CommentParser) GetEnumValues() []EnumValue {
+	return p.enumValues
+}
+
+// GetExample 获取默认示例（@example）的标注
+func (p *CommentParser) GetExample() (ExampleAnnotation, bool) {
+	for _, example := range p.examples {
+		if example.Name == "" {
+			return example, true
+		}
+	}
+	return ExampleAnnotation{}, false
+}
+
+// GetNamedExamples 按声明顺序获取全部命名示例（@example[name]）
+func (p *CommentParser) GetNamedExamples() []ExampleAnnotation {
+	var named []ExampleAnnotation
+	for _, example := range p.examples {
+		if example.Name != "" {
+			named = append(named, example)
+		}
+	}
+	return named
+}
+
+// parseBoolTag 解析布尔类型的标签值，空值视为 true，非法取值记录诊断信息
+func (p *CommentParser) parseBoolTag(tag, value string) {
+	if value == "" {
+		p.boolTags[tag] = true
+		return
+	}
+	if b, err := strconv.ParseBool(value); err == nil {
+		p.boolTags[tag] = b
+	} else {
+		p.addDiagnostic(tag, value, "invalid boolean, expected true or false")
+	}
+}
+
+// Diagnostics 返回解析过程中发现的格式错误、非法取值与未知标注
+func (p *CommentParser) Diagnostics() []AnnotationDiagnostic {
+	return p.diagnostics
+}
+
+func (p *CommentParser) addDiagnostic(tag, raw, format string, args ...any) {
+	p.diagnostics = append(p.diagnostics, AnnotationDiagnostic{Tag: tag, Raw: raw, Reason: fmt.Sprintf(format, args...)})
+}
+
+// GetResponseAnnotations 按声明顺序获取全部 @response 标注
+func (p *CommentParser) GetResponseAnnotations() []ResponseAnnotation {
+	return p.responses
+}
+
+// GetParameterAnnotations 按声明顺序获取 @header、@cookie 与 @query 标注声明的参数
+func (p *CommentParser) GetParameterAnnotations() []Parameter {
+	return p.parameters
+}
+
+// GetResponseHeaderAnnotations 按声明顺序获取指定状态码的 @responseHeader 标注，code 为空时返回全部
+func (p *CommentParser) GetResponseHeaderAnnotations(code string) []ResponseHeaderAnnotation {
+	if code == "" {
+		return p.headers
+	}
+	var result []ResponseHeaderAnnotation
+	for _, annotation := range p.headers {
+		if annotation.Code == code {
+			result = append(result, annotation)
+		}
+	}
+	return result
+}
+
+// GetRoutes 按声明顺序返回 @router 标注的路由
+func (p *CommentParser) GetRoutes() []RouteAnnotation {
+	return p.routes
+}
+
 // HasTag 检查解析器中是否存在指定名称的标签（覆盖字符串、数组、数值、布尔与响应类型）
 func (p *CommentParser) HasTag(tag string) bool {
 	_, hasString := p.tags[tag]
@@ -167,3 +499,230 @@ func (p *CommentParser) ParseOperationDescription(comment string) *OperationDesc
 		Responses:   p.GetResponses(),
 	}
 }
+
+// parseResponseAnnotation 解析 @response 标注的值，状态码不合法时返回 false
+func parseResponseAnnotation(value string) (ResponseAnnotation, bool) {
+	code, rest, found := strings.Cut(value, ":")
+	if !found {
+		return ResponseAnnotation{}, false
+	}
+	code = strings.TrimSpace(code)
+	if !isResponseCode(code) {
+		return ResponseAnnotation{}, false
+	}
+	if code != "default" {
+		code = strings.ToUpper(code)
+	}
+
+	parts := strings.SplitN(rest, "|", 3)
+	for i := range parts {
+		parts[i] = strings.TrimSpace(parts[i])
+	}
+	annotation := ResponseAnnotation{Code: code, Schema: parts[0]}
+	if len(parts) > 1 {
+		annotation.Description = parts[1]
+	}
+	if len(parts) > 2 {
+		annotation.ContentType = parts[2]
+	}
+	return annotation, true
+}
+
+// parameterTypes @header、@cookie 与 @query 标注可声明的参数类型
+var parameterTypes = map[string]bool{"string": true, "integer": true, "number": true, "boolean": true}
+
+// parseParameterAnnotation 解析 @header、@cookie 与 @query 标注的值，格式为 "名称 [类型] [required] ["描述"] [example=值]"。
+// 名称之后的各项顺序不限，类型可带格式如 "string(uuid)"，省略时为 string
+func parseParameterAnnotation(in, value string) (Parameter, error) {
+	fields, err := splitQuoted(value)
+	if err != nil {
+		return Parameter{}, err
+	}
+	if len(fields) == 0 || strings.HasPrefix(fields[0], "\"") {
+		return Parameter{}, errors.New(`expected "<name> [type] [required] [\"description\"] [example=value]"`)
+	}
+
+	param := Parameter{Name: fields[0], In: in, Schema: &Schema{Type: SchemaType{ParamTypeString}}}
+	for _, field := range fields[1:] {
+		typ, format, _ := strings.Cut(strings.TrimSuffix(field, ")"), "(")
+		switch {
+		case strings.HasPrefix(field, "\""):
+			param.Description = strings.Trim(field, "\"")
+		case field == "required":
+			param.Required = true
+		case field == TagDeprecated:
+			param.Deprecated = true
+		case strings.HasPrefix(field, "example="):
+			param.Example = parseExampleValue(strings.TrimPrefix(field, "example="))
+		case parameterTypes[typ]:
+			param.Schema = &Schema{Type: SchemaType{typ}, Format: format}
+		default:
+			return Parameter{}, fmt.Errorf("unexpected %q, expected a type, required, a quoted description or example=value", field)
+		}
+	}
+	return param, nil
+}
+
+// swagParamTypes swag 风格 @param 中的 Go 类型名及其对应的 OpenAPI 类型（可带格式）
+var swagParamTypes = map[string]string{
+	"int":     "integer",
+	"int32":   "integer(int32)",
+	"int64":   "integer(int64)",
+	"uint":    "integer",
+	"uint32":  "integer(int32)",
+	"uint64":  "integer(int64)",
+	"float32": "number(float)",
+	"float64": "number(double)",
+	"bool":    "boolean",
+}
+
+// parseParamAnnotation 解析 @param 标注的值，格式为 "名称 位置 [类型] [required] ["描述"] [example=值]"，
+// 位置为 path、query、header 或 cookie，其余各项与 @header 相同。
+// 同时兼容 swag 的写法 "名称 位置 类型 true|false "描述""，类型可为 int、int64、float64、bool 等 Go 类型名
+func parseParamAnnotation(value string) (Parameter, error) {
+	name, rest, _ := strings.Cut(strings.TrimSpace(value), " ")
+	in, rest, _ := strings.Cut(strings.TrimSpace(rest), " ")
+	switch in {
+	case ParamInPath, ParamInQuery, ParamInHeader, ParamInCookie:
+	default:
+		return Parameter{}, errors.New(`expected "<name> <path|query|header|cookie> [type] [required] [\"description\"] [example=value]"`)
+	}
+	fields, err := splitQuoted(rest)
+	if err != nil {
+		return Parameter{}, err
+	}
+	normalized := []string{name}
+	for _, field := range fields {
+		switch {
+		case field == "true":
+			normalized = append(normalized, "required")
+		case field == "false":
+		case swagParamTypes[field] != "":
+			normalized = append(normalized, swagParamTypes[field])
+		default:
+			normalized = append(normalized, field)
+		}
+	}
+	param, err := parseParameterAnnotation(in, strings.Join(normalized, " "))
+	if err != nil {
+		return Parameter{}, err
+	}
+	if in == ParamInPath {
+		param.Required = true
+	}
+	return param, nil
+}
+
+// parseRouteAnnotation 解析 @router 标注的值，格式为 "路径 [方法]"
+func parseRouteAnnotation(value string) (RouteAnnotation, error) {
+	fields := strings.Fields(value)
+	if len(fields) != 2 || !strings.HasPrefix(fields[0], "/") || !strings.HasPrefix(fields[1], "[") || !strings.HasSuffix(fields[1], "]") {
+		return RouteAnnotation{}, errors.New(`expected "<path> [<method>]", e.g. "/users/{id} [get]"`)
+	}
+	method := strings.ToLower(strings.Trim(fields[1], "[]"))
+	if !slices.Contains(httpMethods, method) {
+		return RouteAnnotation{}, fmt.Errorf("unsupported method %q, expected one of %s", method, strings.Join(httpMethods, ", "))
+	}
+	return RouteAnnotation{Path: fields[0], Method: method}, nil
+}
+
+// parseResponseHeaderAnnotation 解析 @responseHeader 标注的值，状态码之后的部分与 @header 相同
+func parseResponseHeaderAnnotation(value string) (ResponseHeaderAnnotation, error) {
+	code, rest, _ := strings.Cut(strings.TrimSpace(value), " ")
+	code = strings.TrimSuffix(code, ":")
+	if !isResponseCode(code) {
+		return ResponseHeaderAnnotation{}, errors.New(`expected "<code> <name> [type] [required] [\"description\"] [example=value]" with a status code, "4XX" wildcard or "default"`)
+	}
+	if code != "default" {
+		code = strings.ToUpper(code)
+	}
+	param, err := parseParameterAnnotation(ParamInHeader, rest)
+	if err != nil {
+		return ResponseHeaderAnnotation{}, err
+	}
+	return ResponseHeaderAnnotation{
+		Code: code,
+		Name: param.Name,
+		Header: Header{
+			Description: param.Description,
+			Required:    param.Required,
+			Deprecated:  param.Deprecated,
+			Schema:      param.Schema,
+			Example:     param.Example,
+		},
+	}, nil
+}
+
+// splitQuoted 按空白拆分文本，双引号包裹的部分（保留引号）视为一项
+func splitQuoted(value string) ([]string, error) {
+	var fields []string
+	var current strings.Builder
+	inQuote := false
+	for _, r := range value {
+		switch {
+		case r == '"':
+			inQuote = !inQuote
+			current.WriteRune(r)
+		case !inQuote && (r == ' ' || r == '\t'):
+			if current.Len() > 0 {
+				fields = append(fields, current.String())
+				current.Reset()
+			}
+		default:
+			current.WriteRune(r)
+		}
+	}
+	if inQuote {
+		return nil, errors.New("unterminated quoted description")
+	}
+	if current.Len() > 0 {
+		fields = append(fields, current.String())
+	}
+	return fields, nil
+}
+
+// isResponseCode 判断是否为 OpenAPI 允许的响应键：三位状态码、"1XX"~"5XX" 通配或 "default"
+func isResponseCode(code string) bool {
+	if code == "default" {
+		return true
+	}
+	if len(code) != 3 || code[0] < '1' || code[0] > '5' {
+		return false
+	}
+	if strings.EqualFold(code[1:], "XX") {
+		return true
+	}
+	_, err := strconv.Atoi(code)
+	return err == nil
+}
+
+// parseExampleValue 将示例文本按 JSON 解析为带类型的值，非法 JSON 按普通字符串处理
+func parseExampleValue(value string) any {
+	var parsed any
+	if err := json.Unmarshal([]byte(value), &parsed); err == nil {
+		return parsed
+	}
+	return strings.Trim(value, "\"")
+}
+
+// dedent 去除多行文本的公共缩进并按行拼接
+func dedent(lines []string) string {
+	indent := -1
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		n := indentOf(line)
+		if indent < 0 || n < indent {
+			indent = n
+		}
+	}
+	result := make([]string, len(lines))
+	for i, line := range lines {
+		if len(line) >= indent && indent > 0 {
+			line = line[indent:]
+		}
+		result[i] = strings.TrimRight(line, " \t\r")
+	}
+	return strings.Join(result, "\n")
+}
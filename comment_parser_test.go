@@ -0,0 +1,139 @@
+package knife4g
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseFencedBlocks(t *testing.T) {
+	comment := strings.Join([]string{
+		"创建用户",
+		"```go",
+		"// @summary: 不是标注",
+		"@tags: 代码示例",
+		"```",
+		"@summary: 新建",
+		"@description: 详细说明",
+		"```",
+		"@hidden",
+		"```",
+	}, "\n")
+	p := NewCommentParser().Parse(comment)
+
+	if got := p.GetString("summary"); got != "新建" {
+		t.Errorf("summary = %q, want 新建", got)
+	}
+	for _, tag := range []string{TagTags, TagHidden} {
+		if p.HasTag(tag) {
+			t.Errorf("@%s inside a ``` block was parsed as an annotation", tag)
+		}
+	}
+	if want := "详细说明\n```\n@hidden\n```"; p.GetString("description") != want {
+		t.Errorf("description = %q, want %q", p.GetString("description"), want)
+	}
+	if d := p.Diagnostics(); len(d) != 0 {
+		t.Errorf("unexpected diagnostics %v", d)
+	}
+
+	text := NewCommentParser().Parse("说明\n```\n@summary: x\n```")
+	if want := "说明\n```\n@summary: x\n```"; text.GetString("description") != want || text.HasTag("summary") {
+		t.Errorf("description = %q, want %q without a summary", text.GetString("description"), want)
+	}
+}
+
+func TestParseKeepsAtSignText(t *testing.T) {
+	comment := strings.Join([]string{
+		"旧接口",
+		"@Deprecated",
+		"联系 admin@example.com 获取权限",
+		"@admin 负责维护",
+		"@deprecated",
+	}, "\n")
+	p := NewCommentParser().Parse(comment)
+
+	want := "旧接口\n@Deprecated\n联系 admin@example.com 获取权限\n@admin 负责维护"
+	if got := p.GetString("description"); got != want {
+		t.Errorf("description = %q, want %q", got, want)
+	}
+	if !p.GetBool(TagDeprecated) {
+		t.Error("@deprecated without a colon was not recognised")
+	}
+	if d := p.Diagnostics(); len(d) != 0 {
+		t.Errorf("unexpected diagnostics %v", d)
+	}
+
+	if p := NewCommentParser().Parse("@Deprecated"); p.HasTag(TagDeprecated) || p.GetString("description") != "@Deprecated" {
+		t.Error("@Deprecated was treated as @deprecated")
+	}
+}
+
+func TestParseExampleBlock(t *testing.T) {
+	tests := []struct {
+		name      string
+		comment   string
+		wantValue any
+		wantRaw   string
+		wantDiag  bool
+	}{
+		{
+			name:      "terminated",
+			comment:   "@example: ```\n  {\n    \"id\": 1\n  }\n```\n@summary: 详情",
+			wantValue: map[string]any{"id": float64(1)},
+			wantRaw:   "{\n  \"id\": 1\n}",
+		},
+		{
+			name:      "unterminated",
+			comment:   "@example: ```\n{\"id\": 1}\n@summary: 详情",
+			wantValue: "{\"id\": 1}\n@summary: 详情",
+			wantRaw:   "{\"id\": 1}\n@summary: 详情",
+			wantDiag:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewCommentParser().Parse(tt.comment)
+			example, ok := p.GetExample()
+			if !ok {
+				t.Fatal("no example parsed")
+			}
+			if !reflect.DeepEqual(example.Value, tt.wantValue) || example.Raw != tt.wantRaw {
+				t.Errorf("example = %#v (raw %q), want %#v (raw %q)", example.Value, example.Raw, tt.wantValue, tt.wantRaw)
+			}
+			if got := p.GetString("summary") == "详情"; got == tt.wantDiag {
+				t.Errorf("summary parsed = %v, want %v", got, !tt.wantDiag)
+			}
+			diagnostics := p.Diagnostics()
+			if got := len(diagnostics) == 1 && strings.Contains(diagnostics[0].Reason, "unterminated"); got != tt.wantDiag {
+				t.Errorf("diagnostics = %v, want unterminated block: %v", diagnostics, tt.wantDiag)
+			}
+		})
+	}
+}
+
+func TestParseContinuationLines(t *testing.T) {
+	tests := []struct {
+		name            string
+		comment         string
+		wantSummary     string
+		wantDescription string
+	}{
+		{"indented", "@summary: 查询用户\n  并返回分页结果\n\t包含总数\n正文", "查询用户 并返回分页结果 包含总数", "正文"},
+		{"same indent", "@summary: 查询用户\n正文", "查询用户", "正文"},
+		{"blank line ends", "@summary: 查询用户\n\n    缩进代码", "查询用户", "缩进代码"},
+		{"annotation ends", "@summary: 查询用户\n  @deprecated\n  正文", "查询用户", "正文"},
+		{"indented annotation", "  @summary: 查询用户\n    续行\n  正文", "查询用户 续行", "正文"},
+		{"markdown list", "列表：\n- a\n  - b\n@summary: 查询用户", "查询用户", "列表：\n- a\n  - b"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewCommentParser().Parse(tt.comment)
+			if got := p.GetString("summary"); got != tt.wantSummary {
+				t.Errorf("summary = %q, want %q", got, tt.wantSummary)
+			}
+			if got := p.GetString("description"); got != tt.wantDescription {
+				t.Errorf("description = %q, want %q", got, tt.wantDescription)
+			}
+		})
+	}
+}
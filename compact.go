@@ -0,0 +1,84 @@
+package knife4g
+
+import "strings"
+
+// compactDefaultFalse 取值为 false 时与缺省含义相同的字段
+var compactDefaultFalse = map[string]bool{
+	"required":         true,
+	"deprecated":       true,
+	"allowEmptyValue":  true,
+	"allowReserved":    true,
+	"uniqueItems":      true,
+	"nullable":         true,
+	"readOnly":         true,
+	"writeOnly":        true,
+	"exclusiveMinimum": true,
+	"exclusiveMaximum": true,
+}
+
+// compactEmptyString 取值为空字符串时可以省略的字段
+var compactEmptyString = map[string]bool{
+	"summary":     true,
+	"description": true,
+	"operationId": true,
+}
+
+// compactOpaque 值为用户数据的字段，压缩时不进入其内部
+var compactOpaque = map[string]bool{
+	"example": true,
+	"default": true,
+	"enum":    true,
+	"const":   true,
+	"value":   true,
+	"mapping": true,
+	"scopes":  true,
+}
+
+// compactDocument 就地删除输出结构中取缺省值的字段，用于 Config.Compact。
+// 响应对象的 description 为必填字段，即使为空也保留；null 与空的字符串数组（如 tags、required）一并删除
+func compactDocument(doc *orderedMap) {
+	compactNode(doc, false)
+}
+
+func compactNode(node *orderedMap, response bool) {
+	for _, key := range append([]string(nil), node.keys...) {
+		switch value := node.values[key].(type) {
+		case nil:
+			if !compactOpaque[key] && !strings.HasPrefix(key, "x-") {
+				node.Delete(key)
+			}
+		case []string:
+			if len(value) == 0 && !compactOpaque[key] && !strings.HasPrefix(key, "x-") {
+				node.Delete(key)
+			}
+		case bool:
+			if !value && compactDefaultFalse[key] {
+				node.Delete(key)
+			}
+		case string:
+			if value == "" && compactEmptyString[key] && !(response && key == "description") {
+				node.Delete(key)
+			}
+		case *orderedMap:
+			if compactOpaque[key] || strings.HasPrefix(key, "x-") {
+				continue
+			}
+			if key == "responses" {
+				for _, code := range value.keys {
+					if child, ok := value.values[code].(*orderedMap); ok {
+						compactNode(child, true)
+					}
+				}
+				continue
+			}
+			compactNode(value, false)
+		case []*orderedMap:
+			if compactOpaque[key] || strings.HasPrefix(key, "x-") {
+				continue
+			}
+			for _, child := range value {
+				compactNode(child, false)
+			}
+		}
+	}
+}
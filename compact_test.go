@@ -0,0 +1,84 @@
+package knife4g
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// object 按参数顺序构造输出结构，参数依次为键与值
+func object(pairs ...any) *orderedMap {
+	result := newOrderedMap()
+	for i := 0; i < len(pairs); i += 2 {
+		result.Set(pairs[i].(string), pairs[i+1])
+	}
+	return result
+}
+
+func TestCompactDocument(t *testing.T) {
+	schema := object(
+		"type", "object",
+		"required", []string{},
+		"nullable", false,
+		"readOnly", true,
+		"description", "",
+		"properties", object(
+			"name", object("type", "string", "example", "", "default", nil, "deprecated", false),
+			"flag", object("type", "boolean", "enum", []any{false}),
+		),
+		"allOf", []*orderedMap{object("description", "", "uniqueItems", false)},
+	)
+	operation := object(
+		"tags", []string(nil),
+		"summary", "",
+		"description", "",
+		"operationId", "",
+		"deprecated", false,
+		"parameters", []*orderedMap{object("name", "q", "in", "query", "required", false, "allowEmptyValue", false)},
+		"responses", object(
+			"200", object("description", "", "content", object(MIMEApplicationJSON, object("schema", schema))),
+		),
+		"x-empty", object("summary", "", "tags", nil),
+	)
+	doc := object("paths", object("/items", object("get", operation)))
+
+	compactDocument(doc)
+
+	data, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"paths":{"/items":{"get":{` +
+		`"parameters":[{"name":"q","in":"query"}],` +
+		`"responses":{"200":{"description":"","content":{"application/json":{"schema":{` +
+		`"type":"object","readOnly":true,` +
+		`"properties":{"name":{"type":"string","example":"","default":null},"flag":{"type":"boolean","enum":[false]}},` +
+		`"allOf":[{}]}}}}},` +
+		`"x-empty":{"summary":"","tags":null}}}}}`
+	if string(data) != want {
+		t.Errorf("compact output\n%s\nwant\n%s", data, want)
+	}
+}
+
+func TestOperationOmitsEmptyFields(t *testing.T) {
+	doc := &OpenAPI3{
+		OpenAPI: "3.0.3",
+		Info:    Info{Title: "t", Version: "1"},
+		Paths: map[string]PathItem{
+			"/plain":     {Get: &Operation{Responses: map[string]Response{"200": {Description: "OK"}}}},
+			"/annotated": {Get: &Operation{Description: "@summary: 列表\n@tags: items\n@operationId: listItems"}},
+		},
+	}
+	for _, compact := range []bool{false, true} {
+		paths := lookup(serveJSON(t, &Config{OpenAPI: doc, Compact: compact}, "/v3/api-docs"), "paths")
+		plain := keysOf(lookup(paths, "/plain", "get"))
+		for _, key := range []string{"tags", "summary", "operationId"} {
+			if plain[key] {
+				t.Errorf("compact=%v: /plain emitted %q", compact, key)
+			}
+		}
+		annotated := lookup(paths, "/annotated", "get").(map[string]any)
+		if annotated["summary"] != "列表" || annotated["operationId"] != "listItems" || lookup(annotated, "tags") == nil {
+			t.Errorf("compact=%v: /annotated = %v", compact, annotated)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package knife4g
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// AnnotationDiagnostic 描述一个有问题的注释标注
+type AnnotationDiagnostic struct {
+	Pointer string `json:"pointer,omitempty"` // 标注所在对象的 JSON Pointer，如 "#/paths/~1users/get"
+	Tag     string `json:"tag"`
+	Raw     string `json:"raw"`
+	Reason  string `json:"reason"`
+}
+
+func (d AnnotationDiagnostic) String() string {
+	s := "@" + d.Tag + ": " + d.Reason
+	if d.Raw != "" {
+		s += " (" + strconv.Quote(d.Raw) + ")"
+	}
+	if d.Pointer != "" {
+		s = d.Pointer + ": " + s
+	}
+	return s
+}
+
+// AnnotationError 严格模式下文档中存在标注问题时返回的错误
+type AnnotationError struct {
+	Diagnostics []AnnotationDiagnostic
+}
+
+func (e *AnnotationError) Error() string {
+	lines := make([]string, len(e.Diagnostics))
+	for i, d := range e.Diagnostics {
+		lines[i] = d.String()
+	}
+	return fmt.Sprintf("%d annotation problem(s):\n%s", len(e.Diagnostics), strings.Join(lines, "\n"))
+}
+
+// CollectAnnotationDiagnostics 解析文档中全部描述文本，返回按 JSON Pointer 排序的标注问题列表
+func CollectAnnotationDiagnostics(doc *OpenAPI3) []AnnotationDiagnostic {
+	if doc == nil {
+		return nil
+	}
+
+	var diagnostics []AnnotationDiagnostic
+	collect := func(pointer, description string) {
+		if description == "" {
+			return
+		}
+		for _, d := range NewCommentParser().Parse(description).Diagnostics() {
+			d.Pointer = pointer
+			diagnostics = append(diagnostics, d)
+		}
+	}
+
+	collect("#/info", doc.Info.Description)
+	for i, tag := range doc.Tags {
+		collect("#/tags/"+strconv.Itoa(i), tag.Description)
+	}
+	for _, path := range doc.OrderedPaths() {
+		item := doc.Paths[path]
+		pathPointer := "#/paths/" + escapePointer(path)
+		for i, param := range item.Parameters {
+			collect(pathPointer+"/parameters/"+strconv.Itoa(i), param.Description)
+		}
+		for _, op := range item.operations() {
+			pointer := pathPointer + "/" + op.method
+			collect(pointer, op.operation.Description)
+			for i, param := range op.operation.Parameters {
+				collect(pointer+"/parameters/"+strconv.Itoa(i), param.Description)
+			}
+			for _, code := range op.operation.OrderedResponses() {
+				response := op.operation.Responses[code]
+				for _, name := range response.OrderedHeaders() {
+					collect(pointer+"/responses/"+escapePointer(code)+"/headers/"+escapePointer(name), response.Headers[name].Description)
+				}
+			}
+		}
+	}
+	rewriteDocumentSchemas(doc, func(pointer string, schema *Schema) *Schema {
+		collect(pointer, schema.Description)
+		return schema
+	})
+
+	sort.SliceStable(diagnostics, func(i, j int) bool {
+		return diagnostics[i].Pointer < diagnostics[j].Pointer
+	})
+	return diagnostics
+}
+
+// isKnownAnnotation 判断是否为内置或已注册的标注名称
+func isKnownAnnotation(name string) bool {
+	if builtinAnnotations[name] {
+		return true
+	}
+	_, registered := LookupAnnotation(name)
+	return registered
+}
+
+// suggestAnnotation 返回与 name 编辑距离不超过 2 的已知标注名称，用于提示拼写错误
+func suggestAnnotation(name string) string {
+	candidates := make([]string, 0, len(builtinAnnotations))
+	for known := range builtinAnnotations {
+		candidates = append(candidates, known)
+	}
+	for _, annotation := range RegisteredAnnotations() {
+		candidates = append(candidates, annotation.Name)
+	}
+	sort.Strings(candidates)
+
+	best, bestDistance := "", 3
+	for _, candidate := range candidates {
+		if d := editDistance(strings.ToLower(name), strings.ToLower(candidate)); d < bestDistance {
+			best, bestDistance = candidate, d
+		}
+	}
+	return best
+}
+
+// editDistance 计算两个字符串的 Levenshtein 距离
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
@@ -0,0 +1,68 @@
+package knife4g
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// diagnosticsDoc 返回在接口、路径级参数、接口参数与响应头描述中各含一处拼写错误标注的文档
+func diagnosticsDoc() *OpenAPI3 {
+	return &OpenAPI3{
+		OpenAPI: "3.0.3",
+		Info:    Info{Title: "t", Version: "1"},
+		Paths: map[string]PathItem{
+			"/users/{id}": {
+				Parameters: []Parameter{{Name: "id", In: ParamInPath, Required: true, Description: "编号\n@exampel: 1", Schema: stringSchema()}},
+				Get: &Operation{
+					Description: "@summry: 详情",
+					Parameters:  []Parameter{{Name: "q", In: ParamInQuery, Description: "@hiden: true", Schema: stringSchema()}},
+					Responses: map[string]Response{"200": {
+						Description: "OK",
+						Headers:     map[string]Header{"X-Rate-Limit": {Description: "限流\n@exampel: 100", Schema: &Schema{Type: SchemaType{"integer"}}}},
+					}},
+				},
+			},
+		},
+	}
+}
+
+func TestCollectAnnotationDiagnostics(t *testing.T) {
+	want := []string{
+		"#/paths/~1users~1{id}/get: @summry: unknown annotation, did you mean @summary? (\"详情\")",
+		"#/paths/~1users~1{id}/get/parameters/0: @hiden: unknown annotation, did you mean @hidden? (\"true\")",
+		"#/paths/~1users~1{id}/get/responses/200/headers/X-Rate-Limit: @exampel: unknown annotation, did you mean @example? (\"100\")",
+		"#/paths/~1users~1{id}/parameters/0: @exampel: unknown annotation, did you mean @example? (\"1\")",
+	}
+	var got []string
+	for _, d := range CollectAnnotationDiagnostics(diagnosticsDoc()) {
+		got = append(got, d.String())
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("diagnostics = %q\nwant %q", got, want)
+	}
+}
+
+func TestAnnotationCheckStrict(t *testing.T) {
+	server, err := NewKnife4jServer(&Config{OpenAPI: diagnosticsDoc(), AnnotationCheck: AnnotationCheckStrict})
+	if server != nil {
+		t.Error("strict mode returned a server despite annotation problems")
+	}
+	var annotationErr *AnnotationError
+	if !errors.As(err, &annotationErr) {
+		t.Fatalf("err = %v, want *AnnotationError", err)
+	}
+	if got := len(annotationErr.Diagnostics); got != 4 {
+		t.Errorf("got %d diagnostics, want 4: %v", got, annotationErr)
+	}
+
+	for _, mode := range []AnnotationCheckMode{AnnotationCheckOff, AnnotationCheckWarn} {
+		if _, err := NewKnife4jServer(&Config{OpenAPI: diagnosticsDoc(), AnnotationCheck: mode}); err != nil {
+			t.Errorf("mode %d: unexpected error %v", mode, err)
+		}
+	}
+
+	if _, err := NewKnife4jServer(&Config{OpenAPI: visibilityDoc(), AnnotationCheck: AnnotationCheckStrict}); err != nil {
+		t.Errorf("clean document: unexpected error %v", err)
+	}
+}
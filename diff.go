@@ -0,0 +1,479 @@
+package knife4g
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ChangeKind 表示变更的类型
+type ChangeKind string
+
+const (
+	ChangeAdded    ChangeKind = "added"
+	ChangeRemoved  ChangeKind = "removed"
+	ChangeModified ChangeKind = "modified"
+)
+
+// ChangeTarget 表示发生变更的 OpenAPI 元素类别
+type ChangeTarget string
+
+const (
+	TargetOperation   ChangeTarget = "operation"
+	TargetParameter   ChangeTarget = "parameter"
+	TargetRequestBody ChangeTarget = "requestBody"
+	TargetResponse    ChangeTarget = "response"
+	TargetMediaType   ChangeTarget = "mediaType"
+	TargetProperty    ChangeTarget = "property"
+	TargetType        ChangeTarget = "type"
+	TargetEnum        ChangeTarget = "enum"
+	TargetRequired    ChangeTarget = "required"
+	TargetComposition ChangeTarget = "composition"
+)
+
+// Change 表示两个 OpenAPI 版本之间的一条差异
+type Change struct {
+	Kind     ChangeKind   `json:"kind"`
+	Target   ChangeTarget `json:"target"`
+	Location string       `json:"location"` // 变更位置，如 "GET /orders request application/json $.items[].id"
+	Message  string       `json:"message"`
+	Breaking bool         `json:"breaking"`
+}
+
+// ChangeReport 汇总两个 OpenAPI 版本之间的全部差异
+type ChangeReport struct {
+	Changes []Change `json:"changes"`
+}
+
+// schemaDirection 区分 Schema 处于请求还是响应中，两者的兼容性判定规则相反
+type schemaDirection int
+
+const (
+	directionRequest schemaDirection = iota
+	directionResponse
+)
+
+// differ 保存一次 Diff 过程中的上下文
+type differ struct {
+	report     *ChangeReport
+	oldSchemas map[string]Schema
+	newSchemas map[string]Schema
+	visiting   map[string]bool // 正在比较的 $ref 对，防止循环引用导致无限递归
+}
+
+// Diff 比较新旧两个 OpenAPI 文档，列出操作、参数、请求/响应 Schema 属性、枚举值与必填标记的增删改，
+// 并判定每条变更是否为破坏性变更
+func Diff(old, new *OpenAPI3) *ChangeReport {
+	if old == nil {
+		old = &OpenAPI3{}
+	}
+	if new == nil {
+		new = &OpenAPI3{}
+	}
+
+	d := &differ{
+		report:     &ChangeReport{Changes: []Change{}},
+		oldSchemas: old.Components.Schemas,
+		newSchemas: new.Components.Schemas,
+		visiting:   make(map[string]bool),
+	}
+
+	for _, path := range unionKeys(old.Paths, new.Paths) {
+		oldItem, inOld := old.Paths[path]
+		newItem, inNew := new.Paths[path]
+		oldOps := pathOperationMap(oldItem, inOld)
+		newOps := pathOperationMap(newItem, inNew)
+
+		for _, method := range httpMethods {
+			oldOp, newOp := oldOps[method], newOps[method]
+			loc := strings.ToUpper(method) + " " + path
+			switch {
+			case oldOp == nil && newOp == nil:
+				continue
+			case oldOp == nil:
+				d.add(ChangeAdded, TargetOperation, loc, "operation added", false)
+			case newOp == nil:
+				d.add(ChangeRemoved, TargetOperation, loc, "operation removed", true)
+			default:
+				d.diffOperation(loc, oldItem, newItem, oldOp, newOp)
+			}
+		}
+	}
+
+	return d.report
+}
+
+// HasBreaking 判断报告中是否包含破坏性变更
+func (r *ChangeReport) HasBreaking() bool {
+	for _, c := range r.Changes {
+		if c.Breaking {
+			return true
+		}
+	}
+	return false
+}
+
+// Breaking 返回报告中的全部破坏性变更
+func (r *ChangeReport) Breaking() []Change {
+	return r.filter(true)
+}
+
+// NonBreaking 返回报告中的全部非破坏性变更
+func (r *ChangeReport) NonBreaking() []Change {
+	return r.filter(false)
+}
+
+func (r *ChangeReport) filter(breaking bool) []Change {
+	result := make([]Change, 0, len(r.Changes))
+	for _, c := range r.Changes {
+		if c.Breaking == breaking {
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+// JSON 将变更报告渲染为带缩进的 JSON
+func (r *ChangeReport) JSON() ([]byte, error) {
+	return json.MarshalIndent(r, "", "  ")
+}
+
+// Markdown 将变更报告渲染为 Markdown，便于直接粘贴到 PR 描述或发布说明中
+func (r *ChangeReport) Markdown() string {
+	var b strings.Builder
+	breaking, nonBreaking := r.Breaking(), r.NonBreaking()
+
+	b.WriteString("# API Changes\n\n")
+	if len(r.Changes) == 0 {
+		b.WriteString("No changes.\n")
+		return b.String()
+	}
+	fmt.Fprintf(&b, "%d breaking, %d non-breaking\n", len(breaking), len(nonBreaking))
+
+	writeSection := func(title string, changes []Change) {
+		if len(changes) == 0 {
+			return
+		}
+		fmt.Fprintf(&b, "\n## %s\n\n", title)
+		b.WriteString("| Kind | Target | Location | Description |\n")
+		b.WriteString("| --- | --- | --- | --- |\n")
+		for _, c := range changes {
+			fmt.Fprintf(&b, "| %s | %s | `%s` | %s |\n",
+				c.Kind, c.Target, c.Location, strings.ReplaceAll(c.Message, "|", "\\|"))
+		}
+	}
+	writeSection("Breaking changes", breaking)
+	writeSection("Non-breaking changes", nonBreaking)
+
+	return b.String()
+}
+
+func (d *differ) add(kind ChangeKind, target ChangeTarget, loc, message string, breaking bool) {
+	d.report.Changes = append(d.report.Changes, Change{
+		Kind:     kind,
+		Target:   target,
+		Location: loc,
+		Message:  message,
+		Breaking: breaking,
+	})
+}
+
+// diffOperation 比较同一路径、同一方法下的两个 Operation
+func (d *differ) diffOperation(loc string, oldItem, newItem PathItem, oldOp, newOp *Operation) {
+	// 参数：路径级参数与操作级参数合并后按 in + name 比较
+	oldParams := parameterMap(oldItem.Parameters, oldOp.Parameters)
+	newParams := parameterMap(newItem.Parameters, newOp.Parameters)
+	for _, key := range unionKeys(oldParams, newParams) {
+		oldParam, inOld := oldParams[key]
+		newParam, inNew := newParams[key]
+		paramLoc := loc + " parameter " + key
+		switch {
+		case !inOld:
+			d.add(ChangeAdded, TargetParameter, paramLoc, requiredMessage("parameter added", newParam.Required), newParam.Required)
+		case !inNew:
+			d.add(ChangeRemoved, TargetParameter, paramLoc, "parameter removed", true)
+		default:
+			if !oldParam.Required && newParam.Required {
+				d.add(ChangeModified, TargetRequired, paramLoc, "parameter became required", true)
+			} else if oldParam.Required && !newParam.Required {
+				d.add(ChangeModified, TargetRequired, paramLoc, "parameter became optional", false)
+			}
+			d.diffSchema(paramLoc, oldParam.Schema, newParam.Schema, directionRequest)
+		}
+	}
+
+	// 请求体
+	switch {
+	case oldOp.RequestBody == nil && newOp.RequestBody != nil:
+		required := newOp.RequestBody.Required
+		d.add(ChangeAdded, TargetRequestBody, loc+" request", requiredMessage("request body added", required), required)
+	case oldOp.RequestBody != nil && newOp.RequestBody == nil:
+		d.add(ChangeRemoved, TargetRequestBody, loc+" request", "request body removed", false)
+	case oldOp.RequestBody != nil && newOp.RequestBody != nil:
+		if !oldOp.RequestBody.Required && newOp.RequestBody.Required {
+			d.add(ChangeModified, TargetRequired, loc+" request", "request body became required", true)
+		} else if oldOp.RequestBody.Required && !newOp.RequestBody.Required {
+			d.add(ChangeModified, TargetRequired, loc+" request", "request body became optional", false)
+		}
+		d.diffContent(loc+" request", oldOp.RequestBody.Content, newOp.RequestBody.Content, directionRequest)
+	}
+
+	// 响应
+	for _, code := range unionKeys(oldOp.Responses, newOp.Responses) {
+		oldResp, inOld := oldOp.Responses[code]
+		newResp, inNew := newOp.Responses[code]
+		respLoc := loc + " response " + code
+		switch {
+		case !inOld:
+			d.add(ChangeAdded, TargetResponse, respLoc, "response added", false)
+		case !inNew:
+			d.add(ChangeRemoved, TargetResponse, respLoc, "response removed", true)
+		default:
+			d.diffContent(respLoc, oldResp.Content, newResp.Content, directionResponse)
+		}
+	}
+}
+
+// diffContent 比较请求体或响应中按媒体类型划分的内容
+func (d *differ) diffContent(loc string, oldContent, newContent map[string]MediaType, dir schemaDirection) {
+	for _, mediaType := range unionKeys(oldContent, newContent) {
+		oldMedia, inOld := oldContent[mediaType]
+		newMedia, inNew := newContent[mediaType]
+		mediaLoc := loc + " " + mediaType
+		switch {
+		case !inOld:
+			d.add(ChangeAdded, TargetMediaType, mediaLoc, "media type added", false)
+		case !inNew:
+			d.add(ChangeRemoved, TargetMediaType, mediaLoc, "media type removed", true)
+		default:
+			d.diffSchema(mediaLoc+" $", oldMedia.Schema, newMedia.Schema, dir)
+		}
+	}
+}
+
+// diffSchema 递归比较两个 Schema。请求方向上收紧约束（新增必填、新增枚举约束或删除枚举值）为破坏性变更，
+// 响应方向上放宽约束（取消必填、取消枚举约束或新增枚举值、删除属性）为破坏性变更
+func (d *differ) diffSchema(loc string, oldSchema, newSchema *Schema, dir schemaDirection) {
+	if oldSchema == nil || newSchema == nil {
+		return
+	}
+
+	// 对同一对 $ref 只比较一次，避免递归结构无限展开
+	if oldSchema.Ref != "" || newSchema.Ref != "" {
+		key := oldSchema.Ref + "|" + newSchema.Ref + "|" + fmt.Sprint(dir)
+		if d.visiting[key] {
+			return
+		}
+		d.visiting[key] = true
+		defer delete(d.visiting, key)
+	}
+	oldSchema = resolveSchema(oldSchema, d.oldSchemas)
+	newSchema = resolveSchema(newSchema, d.newSchemas)
+
+	oldType, newType := oldSchema.Type.String(), newSchema.Type.String()
+	if oldType != newType && oldType != "" && newType != "" {
+		d.add(ChangeModified, TargetType, loc,
+			fmt.Sprintf("type changed from %q to %q", oldType, newType), true)
+		return
+	}
+	if oldSchema.Format != newSchema.Format && oldSchema.Format != "" && newSchema.Format != "" {
+		d.add(ChangeModified, TargetType, loc,
+			fmt.Sprintf("format changed from %q to %q", oldSchema.Format, newSchema.Format), true)
+	}
+
+	// 枚举值：新增或取消整个枚举约束与增删单个枚举值分别判定
+	switch oldHas, newHas := len(oldSchema.Enum) > 0, len(newSchema.Enum) > 0; {
+	case !oldHas && newHas:
+		d.add(ChangeAdded, TargetEnum, loc, "enum constraint added", dir == directionRequest)
+	case oldHas && !newHas:
+		d.add(ChangeRemoved, TargetEnum, loc, "enum constraint removed", dir == directionResponse)
+	case oldHas && newHas:
+		oldEnum, newEnum := enumSet(oldSchema.Enum), enumSet(newSchema.Enum)
+		for _, v := range sortedKeys(newEnum) {
+			if !oldEnum[v] {
+				d.add(ChangeAdded, TargetEnum, loc, fmt.Sprintf("enum value %s added", v), dir == directionResponse)
+			}
+		}
+		for _, v := range sortedKeys(oldEnum) {
+			if !newEnum[v] {
+				d.add(ChangeRemoved, TargetEnum, loc, fmt.Sprintf("enum value %s removed", v), dir == directionRequest)
+			}
+		}
+	}
+
+	// 必填字段
+	oldRequired, newRequired := stringSet(oldSchema.Required), stringSet(newSchema.Required)
+	for _, name := range sortedKeys(newRequired) {
+		if !oldRequired[name] {
+			if _, existed := oldSchema.Properties[name]; existed {
+				d.add(ChangeModified, TargetRequired, joinLocation(loc, name), "property became required", dir == directionRequest)
+			}
+		}
+	}
+	for _, name := range sortedKeys(oldRequired) {
+		if !newRequired[name] {
+			if _, exists := newSchema.Properties[name]; exists {
+				d.add(ChangeModified, TargetRequired, joinLocation(loc, name), "property became optional", dir == directionResponse)
+			}
+		}
+	}
+
+	// 属性
+	for _, name := range unionKeys(oldSchema.Properties, newSchema.Properties) {
+		oldProp, inOld := oldSchema.Properties[name]
+		newProp, inNew := newSchema.Properties[name]
+		propLoc := joinLocation(loc, name)
+		switch {
+		case !inOld:
+			breaking := dir == directionRequest && newRequired[name]
+			d.add(ChangeAdded, TargetProperty, propLoc, requiredMessage("property added", newRequired[name]), breaking)
+		case !inNew:
+			d.add(ChangeRemoved, TargetProperty, propLoc, "property removed", dir == directionResponse)
+		default:
+			d.diffSchema(propLoc, oldProp, newProp, dir)
+		}
+	}
+
+	if oldSchema.Items != nil || newSchema.Items != nil {
+		d.diffSchema(loc+"[]", oldSchema.Items, newSchema.Items, dir)
+	}
+
+	// 组合关键字：allOf 每个成员都是约束，新增成员收紧、删除成员放宽；
+	// oneOf/anyOf 每个分支都是可选形态，新增分支放宽、删除分支收紧
+	d.diffComposition(loc, "allOf", oldSchema.AllOf, newSchema.AllOf, dir, true)
+	d.diffComposition(loc, "oneOf", oldSchema.OneOf, newSchema.OneOf, dir, false)
+	d.diffComposition(loc, "anyOf", oldSchema.AnyOf, newSchema.AnyOf, dir, false)
+}
+
+// diffComposition 比较组合关键字的成员：$ref 成员按引用名配对，内联成员按出现顺序配对，配对的成员递归比较。
+// conjunctive 为 true 表示 allOf，此时新增成员为收紧约束，否则新增分支为放宽约束
+func (d *differ) diffComposition(loc, keyword string, oldItems, newItems []*Schema, dir schemaDirection, conjunctive bool) {
+	oldMembers, newMembers := compositionMembers(oldItems), compositionMembers(newItems)
+	// 收紧约束在请求方向上破坏兼容，放宽约束在响应方向上破坏兼容
+	tightening, widening := dir == directionRequest, dir == directionResponse
+	addBreaking, removeBreaking := widening, tightening
+	if conjunctive {
+		addBreaking, removeBreaking = tightening, widening
+	}
+
+	for _, key := range unionKeys(oldMembers, newMembers) {
+		oldMember, inOld := oldMembers[key]
+		newMember, inNew := newMembers[key]
+		memberLoc := loc + "." + keyword + "[" + key + "]"
+		switch {
+		case !inOld:
+			d.add(ChangeAdded, TargetComposition, memberLoc, keyword+" schema added", addBreaking)
+		case !inNew:
+			d.add(ChangeRemoved, TargetComposition, memberLoc, keyword+" schema removed", removeBreaking)
+		default:
+			d.diffSchema(memberLoc, oldMember, newMember, dir)
+		}
+	}
+}
+
+// compositionMembers 为组合关键字的成员生成配对用的 key：$ref 成员为引用的 Schema 名称，内联成员为其在内联成员中的序号
+func compositionMembers(items []*Schema) map[string]*Schema {
+	result := make(map[string]*Schema, len(items))
+	inline := 0
+	for _, item := range items {
+		if item == nil {
+			continue
+		}
+		if item.Ref != "" {
+			result[filepath.Base(item.Ref)] = item
+			continue
+		}
+		result[fmt.Sprint(inline)] = item
+		inline++
+	}
+	return result
+}
+
+// resolveSchema 解开 $ref 指向的 Component Schema，找不到时返回原节点
+func resolveSchema(schema *Schema, componentsSchemas map[string]Schema) *Schema {
+	if schema == nil || schema.Ref == "" {
+		return schema
+	}
+	if compSchema, exists := componentsSchemas[filepath.Base(schema.Ref)]; exists {
+		return &compSchema
+	}
+	return schema
+}
+
+// parameterMap 合并路径级与操作级参数，操作级参数覆盖同名的路径级参数
+func parameterMap(pathParams, opParams []Parameter) map[string]Parameter {
+	result := make(map[string]Parameter, len(pathParams)+len(opParams))
+	for _, p := range pathParams {
+		result[p.In+":"+p.Name] = p
+	}
+	for _, p := range opParams {
+		result[p.In+":"+p.Name] = p
+	}
+	return result
+}
+
+// pathOperationMap 以小写 HTTP 方法为 key 返回 PathItem 中定义的 Operation
+func pathOperationMap(item PathItem, exists bool) map[string]*Operation {
+	result := make(map[string]*Operation)
+	if !exists {
+		return result
+	}
+	for _, op := range item.operations() {
+		result[op.method] = op.operation
+	}
+	return result
+}
+
+func requiredMessage(message string, required bool) string {
+	if required {
+		return message + " (required)"
+	}
+	return message
+}
+
+func joinLocation(loc, name string) string {
+	return loc + "." + name
+}
+
+func enumSet(values []interface{}) map[string]bool {
+	result := make(map[string]bool, len(values))
+	for _, v := range values {
+		b, err := json.Marshal(v)
+		if err != nil {
+			result[fmt.Sprint(v)] = true
+			continue
+		}
+		result[string(b)] = true
+	}
+	return result
+}
+
+func stringSet(values []string) map[string]bool {
+	result := make(map[string]bool, len(values))
+	for _, v := range values {
+		result[v] = true
+	}
+	return result
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func unionKeys[V any](a, b map[string]V) []string {
+	seen := make(map[string]bool, len(a)+len(b))
+	for k := range a {
+		seen[k] = true
+	}
+	for k := range b {
+		seen[k] = true
+	}
+	return sortedKeys(seen)
+}
@@ -0,0 +1,189 @@
+package knife4g
+
+import (
+	"testing"
+)
+
+// schemaDoc 返回 POST /orders 的请求体与 200 响应均使用 schema 的文档
+func schemaDoc(schema *Schema, components map[string]Schema) *OpenAPI3 {
+	content := map[string]MediaType{MIMEApplicationJSON: {Schema: schema}}
+	return &OpenAPI3{
+		Paths: map[string]PathItem{
+			"/orders": {Post: &Operation{
+				RequestBody: &RequestBody{Content: content},
+				Responses:   map[string]Response{"200": {Content: content}},
+			}},
+		},
+		Components: Components{Schemas: components},
+	}
+}
+
+func stringSchema(enum ...interface{}) *Schema {
+	return &Schema{Type: SchemaType{"string"}, Enum: enum}
+}
+
+func objectSchema(properties map[string]*Schema) *Schema {
+	return &Schema{Type: SchemaType{"object"}, Properties: properties}
+}
+
+func refSchema(name string) *Schema {
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+// diffResult 按 "位置 → 是否破坏兼容" 汇总变更，同一位置出现多条变更时视为测试数据有误
+func diffResult(t *testing.T, report *ChangeReport) map[string]bool {
+	t.Helper()
+	result := make(map[string]bool, len(report.Changes))
+	for _, c := range report.Changes {
+		if _, exists := result[c.Location]; exists {
+			t.Fatalf("multiple changes at %q: %+v", c.Location, report.Changes)
+		}
+		result[c.Location] = c.Breaking
+	}
+	return result
+}
+
+func TestDiffSchemaClassification(t *testing.T) {
+	const (
+		request  = "POST /orders request application/json $"
+		response = "POST /orders response 200 application/json $"
+	)
+	tests := []struct {
+		name       string
+		old, new   *Schema
+		components [2]map[string]Schema
+		want       map[string]bool
+	}{
+		{
+			name: "enum constraint added",
+			old:  stringSchema(),
+			new:  stringSchema("a", "b"),
+			want: map[string]bool{request: true, response: false},
+		},
+		{
+			name: "enum constraint removed",
+			old:  stringSchema("a", "b"),
+			new:  stringSchema(),
+			want: map[string]bool{request: false, response: true},
+		},
+		{
+			name: "enum value added",
+			old:  stringSchema("a"),
+			new:  stringSchema("a", "b"),
+			want: map[string]bool{request: false, response: true},
+		},
+		{
+			name: "enum value removed",
+			old:  stringSchema("a", "b"),
+			new:  stringSchema("a"),
+			want: map[string]bool{request: true, response: false},
+		},
+		{
+			name: "enum unchanged",
+			old:  stringSchema("a", "b"),
+			new:  stringSchema("b", "a"),
+			want: map[string]bool{},
+		},
+		{
+			name: "allOf member added",
+			old:  &Schema{AllOf: []*Schema{refSchema("Base")}},
+			new:  &Schema{AllOf: []*Schema{refSchema("Base"), refSchema("Audit")}},
+			want: map[string]bool{request + ".allOf[Audit]": true, response + ".allOf[Audit]": false},
+		},
+		{
+			name: "allOf member removed",
+			old:  &Schema{AllOf: []*Schema{refSchema("Base"), refSchema("Audit")}},
+			new:  &Schema{AllOf: []*Schema{refSchema("Base")}},
+			want: map[string]bool{request + ".allOf[Audit]": false, response + ".allOf[Audit]": true},
+		},
+		{
+			name: "oneOf branch added",
+			old:  &Schema{OneOf: []*Schema{refSchema("Card")}},
+			new:  &Schema{OneOf: []*Schema{refSchema("Card"), refSchema("Bank")}},
+			want: map[string]bool{request + ".oneOf[Bank]": false, response + ".oneOf[Bank]": true},
+		},
+		{
+			name: "anyOf branch removed",
+			old:  &Schema{AnyOf: []*Schema{refSchema("Card"), refSchema("Bank")}},
+			new:  &Schema{AnyOf: []*Schema{refSchema("Card")}},
+			want: map[string]bool{request + ".anyOf[Bank]": true, response + ".anyOf[Bank]": false},
+		},
+		{
+			name: "allOf member changed",
+			old: &Schema{AllOf: []*Schema{refSchema("Base"), objectSchema(map[string]*Schema{
+				"note": {Type: SchemaType{"string"}},
+			})}},
+			new: &Schema{AllOf: []*Schema{refSchema("Base"), objectSchema(map[string]*Schema{
+				"note": {Type: SchemaType{"integer"}},
+			})}},
+			want: map[string]bool{request + ".allOf[0].note": true, response + ".allOf[0].note": true},
+		},
+		{
+			name: "oneOf referenced schema changed",
+			old:  &Schema{OneOf: []*Schema{refSchema("Card")}},
+			new:  &Schema{OneOf: []*Schema{refSchema("Card")}},
+			components: [2]map[string]Schema{
+				{"Card": *objectSchema(map[string]*Schema{"brand": stringSchema("visa")})},
+				{"Card": *objectSchema(map[string]*Schema{"brand": stringSchema("visa", "amex")})},
+			},
+			want: map[string]bool{request + ".oneOf[Card].brand": false, response + ".oneOf[Card].brand": true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := Diff(schemaDoc(tt.old, tt.components[0]), schemaDoc(tt.new, tt.components[1]))
+			got := diffResult(t, report)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got changes %+v, want %v", report.Changes, tt.want)
+			}
+			for loc, breaking := range tt.want {
+				gotBreaking, ok := got[loc]
+				if !ok {
+					t.Errorf("missing change at %q, got %+v", loc, report.Changes)
+					continue
+				}
+				if gotBreaking != breaking {
+					t.Errorf("%q: breaking = %v, want %v", loc, gotBreaking, breaking)
+				}
+			}
+		})
+	}
+}
+
+func TestDiffOperations(t *testing.T) {
+	old := &OpenAPI3{Paths: map[string]PathItem{
+		"/orders": {
+			Parameters: []Parameter{{Name: "tenant", In: ParamInHeader}},
+			Get:        &Operation{Responses: map[string]Response{"200": {}}},
+			Delete:     &Operation{},
+		},
+	}}
+	new := &OpenAPI3{Paths: map[string]PathItem{
+		"/orders": {
+			Parameters: []Parameter{{Name: "tenant", In: ParamInHeader, Required: true}},
+			Get: &Operation{
+				Parameters: []Parameter{{Name: "page", In: ParamInQuery}},
+				Responses:  map[string]Response{"200": {}, "404": {}},
+			},
+			Post: &Operation{},
+		},
+	}}
+
+	want := map[string]bool{
+		"GET /orders parameter header:tenant": true,
+		"GET /orders parameter query:page":    false,
+		"GET /orders response 404":            false,
+		"POST /orders":                        false,
+		"DELETE /orders":                      true,
+	}
+	got := diffResult(t, Diff(old, new))
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for loc, breaking := range want {
+		if gotBreaking, ok := got[loc]; !ok || gotBreaking != breaking {
+			t.Errorf("%q: got (%v, %v), want breaking = %v", loc, gotBreaking, ok, breaking)
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package knife4g
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	ExtEnumVarNames     = "x-enum-varnames"
+	ExtEnumDescriptions = "x-enum-descriptions"
+)
+
+// EnumValue 表示 @enum 标注中的一个枚举值，如 "1=Pending: 待支付" 中的值、名称与说明
+type EnumValue struct {
+	Value       string // 原始文本，输出时按 Schema 类型转换
+	Name        string // 代码生成使用的常量名，对应 x-enum-varnames
+	Description string // 对应 x-enum-descriptions
+}
+
+// enumIdentifier 匹配可作为常量名的枚举名称
+var enumIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// parseEnumAnnotation 解析 @enum 标注的值，支持 "[1,2,3]"、"1=Pending, 2=Paid" 与 "1=Pending: 待支付"。
+// 值中含分号时按分号分隔，以便说明文字中使用逗号；"=" 后不是合法标识符的文本视为说明
+func parseEnumAnnotation(value string) []EnumValue {
+	value = strings.TrimSpace(value)
+	if strings.HasPrefix(value, "[") && strings.HasSuffix(value, "]") {
+		value = value[1 : len(value)-1]
+	}
+	separator := ","
+	if strings.Contains(value, ";") {
+		separator = ";"
+	}
+
+	var result []EnumValue
+	for _, item := range strings.Split(value, separator) {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		raw, label, _ := strings.Cut(item, "=")
+		enum := EnumValue{Value: strings.TrimSpace(raw)}
+		label = strings.TrimSpace(label)
+		if name, description, found := strings.Cut(label, ":"); found && enumIdentifier.MatchString(strings.TrimSpace(name)) {
+			enum.Name, enum.Description = strings.TrimSpace(name), strings.TrimSpace(description)
+		} else if enumIdentifier.MatchString(label) {
+			enum.Name = label
+		} else {
+			enum.Description = label
+		}
+		result = append(result, enum)
+	}
+	return result
+}
+
+// enumLiteral 按 Schema 类型转换枚举值文本，使整数枚举输出为 1 而不是 "1"
+func enumLiteral(raw string, schema *Schema) any {
+	switch {
+	case schema == nil || len(schema.Type) == 0:
+		return parseExampleValue(raw)
+	case schema.Type.Is("integer"):
+		if n, err := strconv.ParseInt(raw, 10, 64); err == nil {
+			return n
+		}
+	case schema.Type.Is("number"):
+		if n, err := strconv.ParseFloat(raw, 64); err == nil {
+			return n
+		}
+	case schema.Type.Is("boolean"):
+		if b, err := strconv.ParseBool(raw); err == nil {
+			return b
+		}
+	}
+	return strings.Trim(raw, "\"")
+}
+
+// schemaEnumValues 从 Schema 自身的 enum 与 x-enum-varnames/x-enum-descriptions 扩展中读取枚举说明
+func schemaEnumValues(schema *Schema) []EnumValue {
+	rawNames, _ := schema.Extensions.Get(ExtEnumVarNames)
+	rawDescriptions, _ := schema.Extensions.Get(ExtEnumDescriptions)
+	names, descriptions := stringList(rawNames), stringList(rawDescriptions)
+	result := make([]EnumValue, len(schema.Enum))
+	for i, value := range schema.Enum {
+		result[i].Value = fmt.Sprint(value)
+		if i < len(names) {
+			result[i].Name = names[i]
+		}
+		if i < len(descriptions) {
+			result[i].Description = descriptions[i]
+		}
+	}
+	return result
+}
+
+func stringList(value any) []string {
+	items, ok := value.([]any)
+	if !ok {
+		return nil
+	}
+	result := make([]string, len(items))
+	for i, item := range items {
+		result[i] = fmt.Sprint(item)
+	}
+	return result
+}
+
+// applyEnumAnnotation 输出带类型的 enum，以及 x-enum-varnames、x-enum-descriptions 与描述末尾的枚举说明表格。
+// @enum 标注优先于 Schema 自身的 enum
+func applyEnumAnnotation(result *orderedMap, parser *CommentParser, schema *Schema) {
+	values := parser.GetEnumValues()
+	if len(values) > 0 {
+		enum := make([]any, len(values))
+		for i, v := range values {
+			enum[i] = enumLiteral(v.Value, schema)
+		}
+		result.Set("enum", enum)
+	} else if len(schema.Enum) > 0 {
+		values = schemaEnumValues(schema)
+	}
+
+	var hasName, hasDescription bool
+	for _, v := range values {
+		hasName = hasName || v.Name != ""
+		hasDescription = hasDescription || v.Description != ""
+	}
+	if !hasName && !hasDescription {
+		return
+	}
+
+	names := make([]string, len(values))
+	descriptions := make([]string, len(values))
+	header, divider := "| Value |", "| --- |"
+	if hasName {
+		header, divider = header+" Name |", divider+" --- |"
+	}
+	if hasDescription {
+		header, divider = header+" Description |", divider+" --- |"
+	}
+	table := []string{header, divider}
+	for i, v := range values {
+		names[i], descriptions[i] = v.Name, v.Description
+		row := "| " + escapeTableCell(v.Value) + " |"
+		if hasName {
+			row += " " + escapeTableCell(v.Name) + " |"
+		}
+		if hasDescription {
+			row += " " + escapeTableCell(v.Description) + " |"
+		}
+		table = append(table, row)
+	}
+	if hasName {
+		result.Set(ExtEnumVarNames, names)
+	}
+	if hasDescription {
+		result.Set(ExtEnumDescriptions, descriptions)
+	}
+
+	description, _ := result.Get("description")
+	text, _ := description.(string)
+	if text != "" {
+		text += "\n\n"
+	}
+	result.Set("description", text+strings.Join(table, "\n"))
+}
+
+func escapeTableCell(text string) string {
+	return strings.ReplaceAll(text, "|", `\|`)
+}
@@ -0,0 +1,739 @@
+package knife4g
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Knife4j 识别的 x- 扩展字段
+const (
+	ExtOrder  = "x-order"  // 分组（tag）与接口在左侧菜单中的排序
+	ExtAuthor = "x-author" // 接口开发者
+)
+
+// Extensions 保存模型中 x- 前缀的扩展字段以及未被结构体字段识别的其他字段，
+// 反序列化时自动收集，序列化时原样写回，保证文档往返不丢失信息
+type Extensions map[string]any
+
+// Get 获取指定扩展字段的值
+func (e Extensions) Get(key string) (any, bool) {
+	v, ok := e[key]
+	return v, ok
+}
+
+// GetString 获取字符串类型的扩展字段，不存在或类型不匹配时返回空字符串
+func (e Extensions) GetString(key string) string {
+	s, _ := e[key].(string)
+	return s
+}
+
+// Set 设置扩展字段，必要时初始化 map
+func (e *Extensions) Set(key string, value any) {
+	if *e == nil {
+		*e = make(Extensions)
+	}
+	(*e)[key] = value
+}
+
+// Order 获取 Knife4j 的 x-order 排序值，兼容数值与数字字符串
+func (e Extensions) Order() (int, bool) {
+	switch v := e[ExtOrder].(type) {
+	case int:
+		return v, true
+	case int64:
+		return int(v), true
+	case float64:
+		return int(v), true
+	case string:
+		if n, err := strconv.Atoi(strings.TrimSpace(v)); err == nil {
+			return n, true
+		}
+	}
+	return 0, false
+}
+
+// SetOrder 设置 Knife4j 的 x-order 排序值
+func (e *Extensions) SetOrder(order int) {
+	e.Set(ExtOrder, order)
+}
+
+// Author 获取 Knife4j 的 x-author 接口开发者
+func (e Extensions) Author() string {
+	return e.GetString(ExtAuthor)
+}
+
+// SetAuthor 设置 Knife4j 的 x-author 接口开发者
+func (e *Extensions) SetAuthor(author string) {
+	e.Set(ExtAuthor, author)
+}
+
+// copyExtensions 将 x- 扩展字段按键名顺序追加到转换后的输出结构中，不覆盖转换过程已经写入的键
+func copyExtensions(target *orderedMap, ext Extensions) {
+	for _, key := range sortedKeys(ext) {
+		if !strings.HasPrefix(key, "x-") || target.Has(key) {
+			continue
+		}
+		target.Set(key, ext[key])
+	}
+}
+
+// knownFieldsCache 缓存各类型结构体字段对应的 json/yaml 键名
+var knownFieldsCache sync.Map
+
+// knownFields 返回结构体类型按指定 tag（json 或 yaml）声明的全部键名，未命名的内嵌结构体展开其字段
+func knownFields(t reflect.Type, tagKey string) map[string]bool {
+	cacheKey := t.String() + "/" + tagKey
+	if cached, ok := knownFieldsCache.Load(cacheKey); ok {
+		return cached.(map[string]bool)
+	}
+	fields := make(map[string]bool, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get(tagKey), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" && t.Field(i).Anonymous && t.Field(i).Type.Kind() == reflect.Struct {
+			for embedded := range knownFields(t.Field(i).Type, tagKey) {
+				fields[embedded] = true
+			}
+			continue
+		}
+		if name == "" {
+			name = t.Field(i).Name
+			if tagKey == "yaml" {
+				name = strings.ToLower(name)
+			}
+		}
+		fields[name] = true
+	}
+	knownFieldsCache.Store(cacheKey, fields)
+	return fields
+}
+
+// unmarshalYAMLExtensions 使用 target 的默认规则解码 YAML 节点，并将未声明的键收集到 ext
+func unmarshalYAMLExtensions[T any](value *yaml.Node, target *T, ext *Extensions) error {
+	if err := value.Decode(target); err != nil {
+		return err
+	}
+	if value.Kind != yaml.MappingNode {
+		return nil
+	}
+	known := knownFields(reflect.TypeOf(target).Elem(), "yaml")
+	for i := 0; i+1 < len(value.Content); i += 2 {
+		key := value.Content[i].Value
+		if known[key] || key == "<<" {
+			continue
+		}
+		var v any
+		if err := value.Content[i+1].Decode(&v); err != nil {
+			return err
+		}
+		ext.Set(key, v)
+	}
+	return nil
+}
+
+// unmarshalJSONExtensions 使用 target 的默认规则解码 JSON，并将未声明的键收集到 ext
+func unmarshalJSONExtensions[T any](data []byte, target *T, ext *Extensions) error {
+	if err := json.Unmarshal(data, target); err != nil {
+		return err
+	}
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil
+	}
+	known := knownFields(reflect.TypeOf(target).Elem(), "json")
+	for key, msg := range raw {
+		if known[key] {
+			continue
+		}
+		var v any
+		if err := json.Unmarshal(msg, &v); err != nil {
+			return err
+		}
+		ext.Set(key, v)
+	}
+	return nil
+}
+
+// marshalJSONExtensions 按 v 的默认规则编码 JSON，并按键名顺序追加扩展字段
+func marshalJSONExtensions(v any, ext Extensions) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil || len(ext) == 0 {
+		return data, err
+	}
+	known := knownFields(reflect.TypeOf(v), "json")
+
+	var buf bytes.Buffer
+	buf.Write(data[:len(data)-1])
+	first := bytes.Equal(bytes.TrimSpace(data), []byte("{}"))
+	keys := make([]string, 0, len(ext))
+	for key := range ext {
+		if !known[key] {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		value, err := json.Marshal(ext[key])
+		if err != nil {
+			return nil, err
+		}
+		if !first {
+			buf.WriteByte(',')
+		}
+		first = false
+		name, _ := json.Marshal(key)
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// marshalYAMLExtensions 按 v 的默认规则编码 YAML 节点，并按键名顺序追加扩展字段
+func marshalYAMLExtensions(v any, ext Extensions) (interface{}, error) {
+	var node yaml.Node
+	if err := node.Encode(v); err != nil {
+		return nil, err
+	}
+	if len(ext) == 0 || node.Kind != yaml.MappingNode {
+		return &node, nil
+	}
+	known := knownFields(reflect.TypeOf(v), "yaml")
+	keys := make([]string, 0, len(ext))
+	for key := range ext {
+		if !known[key] {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		var valueNode yaml.Node
+		if err := valueNode.Encode(ext[key]); err != nil {
+			return nil, err
+		}
+		node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, &valueNode)
+	}
+	return &node, nil
+}
+
+// 以下为各模型类型的序列化方法：xxxFields 为去掉方法集的同构类型，用于复用默认编解码逻辑，避免递归调用
+
+type openAPI3Fields OpenAPI3
+
+func (o OpenAPI3) MarshalJSON() ([]byte, error) {
+	data, err := marshalJSONExtensions(openAPI3Fields(o), o.Extensions)
+	if err != nil {
+		return nil, err
+	}
+	return reorderJSONMember(data, "paths", o.pathOrder)
+}
+
+func (o OpenAPI3) MarshalYAML() (interface{}, error) {
+	node, err := marshalYAMLExtensions(openAPI3Fields(o), o.Extensions)
+	reorderYAMLMember(node, "paths", o.pathOrder)
+	return node, err
+}
+
+func (o *OpenAPI3) UnmarshalJSON(data []byte) error {
+	if err := unmarshalJSONExtensions(data, (*openAPI3Fields)(o), &o.Extensions); err != nil {
+		return err
+	}
+	o.pathOrder = jsonMemberKeys(data, "paths")
+	return nil
+}
+
+func (o *OpenAPI3) UnmarshalYAML(value *yaml.Node) error {
+	if err := unmarshalYAMLExtensions(value, (*openAPI3Fields)(o), &o.Extensions); err != nil {
+		return err
+	}
+	o.pathOrder = yamlMemberKeys(value, "paths")
+	return nil
+}
+
+type infoFields Info
+
+func (i Info) MarshalJSON() ([]byte, error) {
+	return marshalJSONExtensions(infoFields(i), i.Extensions)
+}
+
+func (i Info) MarshalYAML() (interface{}, error) {
+	return marshalYAMLExtensions(infoFields(i), i.Extensions)
+}
+
+func (i *Info) UnmarshalJSON(data []byte) error {
+	return unmarshalJSONExtensions(data, (*infoFields)(i), &i.Extensions)
+}
+
+func (i *Info) UnmarshalYAML(value *yaml.Node) error {
+	return unmarshalYAMLExtensions(value, (*infoFields)(i), &i.Extensions)
+}
+
+type contactFields Contact
+
+func (c Contact) MarshalJSON() ([]byte, error) {
+	return marshalJSONExtensions(contactFields(c), c.Extensions)
+}
+
+func (c Contact) MarshalYAML() (interface{}, error) {
+	return marshalYAMLExtensions(contactFields(c), c.Extensions)
+}
+
+func (c *Contact) UnmarshalJSON(data []byte) error {
+	return unmarshalJSONExtensions(data, (*contactFields)(c), &c.Extensions)
+}
+
+func (c *Contact) UnmarshalYAML(value *yaml.Node) error {
+	return unmarshalYAMLExtensions(value, (*contactFields)(c), &c.Extensions)
+}
+
+type serverFields Server
+
+func (s Server) MarshalJSON() ([]byte, error) {
+	return marshalJSONExtensions(serverFields(s), s.Extensions)
+}
+
+func (s Server) MarshalYAML() (interface{}, error) {
+	return marshalYAMLExtensions(serverFields(s), s.Extensions)
+}
+
+func (s *Server) UnmarshalJSON(data []byte) error {
+	return unmarshalJSONExtensions(data, (*serverFields)(s), &s.Extensions)
+}
+
+func (s *Server) UnmarshalYAML(value *yaml.Node) error {
+	return unmarshalYAMLExtensions(value, (*serverFields)(s), &s.Extensions)
+}
+
+type serverVariableFields ServerVariable
+
+func (s ServerVariable) MarshalJSON() ([]byte, error) {
+	return marshalJSONExtensions(serverVariableFields(s), s.Extensions)
+}
+
+func (s ServerVariable) MarshalYAML() (interface{}, error) {
+	return marshalYAMLExtensions(serverVariableFields(s), s.Extensions)
+}
+
+func (s *ServerVariable) UnmarshalJSON(data []byte) error {
+	return unmarshalJSONExtensions(data, (*serverVariableFields)(s), &s.Extensions)
+}
+
+func (s *ServerVariable) UnmarshalYAML(value *yaml.Node) error {
+	return unmarshalYAMLExtensions(value, (*serverVariableFields)(s), &s.Extensions)
+}
+
+type pathItemFields PathItem
+
+func (p PathItem) MarshalJSON() ([]byte, error) {
+	return marshalJSONExtensions(pathItemFields(p), p.Extensions)
+}
+
+func (p PathItem) MarshalYAML() (interface{}, error) {
+	return marshalYAMLExtensions(pathItemFields(p), p.Extensions)
+}
+
+func (p *PathItem) UnmarshalJSON(data []byte) error {
+	return unmarshalJSONExtensions(data, (*pathItemFields)(p), &p.Extensions)
+}
+
+func (p *PathItem) UnmarshalYAML(value *yaml.Node) error {
+	return unmarshalYAMLExtensions(value, (*pathItemFields)(p), &p.Extensions)
+}
+
+type operationFields Operation
+
+func (o Operation) MarshalJSON() ([]byte, error) {
+	data, err := marshalJSONExtensions(operationFields(o), o.Extensions)
+	if err != nil {
+		return nil, err
+	}
+	return reorderJSONMember(data, "responses", o.responseOrder)
+}
+
+func (o Operation) MarshalYAML() (interface{}, error) {
+	node, err := marshalYAMLExtensions(operationFields(o), o.Extensions)
+	reorderYAMLMember(node, "responses", o.responseOrder)
+	return node, err
+}
+
+func (o *Operation) UnmarshalJSON(data []byte) error {
+	if err := unmarshalJSONExtensions(data, (*operationFields)(o), &o.Extensions); err != nil {
+		return err
+	}
+	o.responseOrder = jsonMemberKeys(data, "responses")
+	return nil
+}
+
+func (o *Operation) UnmarshalYAML(value *yaml.Node) error {
+	if err := unmarshalYAMLExtensions(value, (*operationFields)(o), &o.Extensions); err != nil {
+		return err
+	}
+	o.responseOrder = yamlMemberKeys(value, "responses")
+	return nil
+}
+
+type parameterFields Parameter
+
+func (p Parameter) MarshalJSON() ([]byte, error) {
+	return marshalJSONExtensions(parameterFields(p), p.Extensions)
+}
+
+func (p Parameter) MarshalYAML() (interface{}, error) {
+	return marshalYAMLExtensions(parameterFields(p), p.Extensions)
+}
+
+func (p *Parameter) UnmarshalJSON(data []byte) error {
+	return unmarshalJSONExtensions(data, (*parameterFields)(p), &p.Extensions)
+}
+
+func (p *Parameter) UnmarshalYAML(value *yaml.Node) error {
+	return unmarshalYAMLExtensions(value, (*parameterFields)(p), &p.Extensions)
+}
+
+type requestBodyFields RequestBody
+
+func (r RequestBody) MarshalJSON() ([]byte, error) {
+	data, err := marshalJSONExtensions(requestBodyFields(r), r.Extensions)
+	if err != nil {
+		return nil, err
+	}
+	return reorderJSONMember(data, "content", r.contentOrder)
+}
+
+func (r RequestBody) MarshalYAML() (interface{}, error) {
+	node, err := marshalYAMLExtensions(requestBodyFields(r), r.Extensions)
+	reorderYAMLMember(node, "content", r.contentOrder)
+	return node, err
+}
+
+func (r *RequestBody) UnmarshalJSON(data []byte) error {
+	if err := unmarshalJSONExtensions(data, (*requestBodyFields)(r), &r.Extensions); err != nil {
+		return err
+	}
+	r.contentOrder = jsonMemberKeys(data, "content")
+	return nil
+}
+
+func (r *RequestBody) UnmarshalYAML(value *yaml.Node) error {
+	if err := unmarshalYAMLExtensions(value, (*requestBodyFields)(r), &r.Extensions); err != nil {
+		return err
+	}
+	r.contentOrder = yamlMemberKeys(value, "content")
+	return nil
+}
+
+type responseFields Response
+
+func (r Response) MarshalJSON() ([]byte, error) {
+	data, err := marshalJSONExtensions(responseFields(r), r.Extensions)
+	if err != nil {
+		return nil, err
+	}
+	if data, err = reorderJSONMember(data, "headers", r.headerOrder); err != nil {
+		return nil, err
+	}
+	if data, err = reorderJSONMember(data, "content", r.contentOrder); err != nil {
+		return nil, err
+	}
+	return reorderJSONMember(data, "links", r.linkOrder)
+}
+
+func (r Response) MarshalYAML() (interface{}, error) {
+	node, err := marshalYAMLExtensions(responseFields(r), r.Extensions)
+	reorderYAMLMember(node, "headers", r.headerOrder)
+	reorderYAMLMember(node, "content", r.contentOrder)
+	reorderYAMLMember(node, "links", r.linkOrder)
+	return node, err
+}
+
+func (r *Response) UnmarshalJSON(data []byte) error {
+	if err := unmarshalJSONExtensions(data, (*responseFields)(r), &r.Extensions); err != nil {
+		return err
+	}
+	r.contentOrder = jsonMemberKeys(data, "content")
+	r.headerOrder = jsonMemberKeys(data, "headers")
+	r.linkOrder = jsonMemberKeys(data, "links")
+	return nil
+}
+
+func (r *Response) UnmarshalYAML(value *yaml.Node) error {
+	if err := unmarshalYAMLExtensions(value, (*responseFields)(r), &r.Extensions); err != nil {
+		return err
+	}
+	r.contentOrder = yamlMemberKeys(value, "content")
+	r.headerOrder = yamlMemberKeys(value, "headers")
+	r.linkOrder = yamlMemberKeys(value, "links")
+	return nil
+}
+
+type componentsFields Components
+
+func (c Components) MarshalJSON() ([]byte, error) {
+	data, err := marshalJSONExtensions(componentsFields(c), c.Extensions)
+	if err != nil {
+		return nil, err
+	}
+	return reorderJSONMember(data, "schemas", c.schemaOrder)
+}
+
+func (c Components) MarshalYAML() (interface{}, error) {
+	node, err := marshalYAMLExtensions(componentsFields(c), c.Extensions)
+	reorderYAMLMember(node, "schemas", c.schemaOrder)
+	return node, err
+}
+
+func (c *Components) UnmarshalJSON(data []byte) error {
+	if err := unmarshalJSONExtensions(data, (*componentsFields)(c), &c.Extensions); err != nil {
+		return err
+	}
+	c.schemaOrder = jsonMemberKeys(data, "schemas")
+	return nil
+}
+
+func (c *Components) UnmarshalYAML(value *yaml.Node) error {
+	if err := unmarshalYAMLExtensions(value, (*componentsFields)(c), &c.Extensions); err != nil {
+		return err
+	}
+	c.schemaOrder = yamlMemberKeys(value, "schemas")
+	return nil
+}
+
+type schemaFields Schema
+
+func (s Schema) MarshalJSON() ([]byte, error) {
+	data, err := marshalJSONExtensions(schemaFields(s), s.Extensions)
+	if err != nil {
+		return nil, err
+	}
+	return reorderJSONMember(data, "properties", s.propertyOrder)
+}
+
+func (s Schema) MarshalYAML() (interface{}, error) {
+	node, err := marshalYAMLExtensions(schemaFields(s), s.Extensions)
+	reorderYAMLMember(node, "properties", s.propertyOrder)
+	return node, err
+}
+
+func (s *Schema) UnmarshalJSON(data []byte) error {
+	if err := unmarshalJSONExtensions(data, (*schemaFields)(s), &s.Extensions); err != nil {
+		return err
+	}
+	s.propertyOrder = jsonMemberKeys(data, "properties")
+	return nil
+}
+
+func (s *Schema) UnmarshalYAML(value *yaml.Node) error {
+	if err := unmarshalYAMLExtensions(value, (*schemaFields)(s), &s.Extensions); err != nil {
+		return err
+	}
+	s.propertyOrder = yamlMemberKeys(value, "properties")
+	return nil
+}
+
+type mediaTypeFields MediaType
+
+func (m MediaType) MarshalJSON() ([]byte, error) {
+	return marshalJSONExtensions(mediaTypeFields(m), m.Extensions)
+}
+
+func (m MediaType) MarshalYAML() (interface{}, error) {
+	return marshalYAMLExtensions(mediaTypeFields(m), m.Extensions)
+}
+
+func (m *MediaType) UnmarshalJSON(data []byte) error {
+	return unmarshalJSONExtensions(data, (*mediaTypeFields)(m), &m.Extensions)
+}
+
+func (m *MediaType) UnmarshalYAML(value *yaml.Node) error {
+	return unmarshalYAMLExtensions(value, (*mediaTypeFields)(m), &m.Extensions)
+}
+
+type exampleFields Example
+
+func (e Example) MarshalJSON() ([]byte, error) {
+	return marshalJSONExtensions(exampleFields(e), e.Extensions)
+}
+
+func (e Example) MarshalYAML() (interface{}, error) {
+	return marshalYAMLExtensions(exampleFields(e), e.Extensions)
+}
+
+func (e *Example) UnmarshalJSON(data []byte) error {
+	return unmarshalJSONExtensions(data, (*exampleFields)(e), &e.Extensions)
+}
+
+func (e *Example) UnmarshalYAML(value *yaml.Node) error {
+	return unmarshalYAMLExtensions(value, (*exampleFields)(e), &e.Extensions)
+}
+
+type headerFields Header
+
+func (h Header) MarshalJSON() ([]byte, error) {
+	return marshalJSONExtensions(headerFields(h), h.Extensions)
+}
+
+func (h Header) MarshalYAML() (interface{}, error) {
+	return marshalYAMLExtensions(headerFields(h), h.Extensions)
+}
+
+func (h *Header) UnmarshalJSON(data []byte) error {
+	return unmarshalJSONExtensions(data, (*headerFields)(h), &h.Extensions)
+}
+
+func (h *Header) UnmarshalYAML(value *yaml.Node) error {
+	return unmarshalYAMLExtensions(value, (*headerFields)(h), &h.Extensions)
+}
+
+type tagFields Tag
+
+func (t Tag) MarshalJSON() ([]byte, error) {
+	return marshalJSONExtensions(tagFields(t), t.Extensions)
+}
+
+func (t Tag) MarshalYAML() (interface{}, error) {
+	return marshalYAMLExtensions(tagFields(t), t.Extensions)
+}
+
+func (t *Tag) UnmarshalJSON(data []byte) error {
+	return unmarshalJSONExtensions(data, (*tagFields)(t), &t.Extensions)
+}
+
+func (t *Tag) UnmarshalYAML(value *yaml.Node) error {
+	return unmarshalYAMLExtensions(value, (*tagFields)(t), &t.Extensions)
+}
+
+type externalDocumentationFields ExternalDocumentation
+
+func (e ExternalDocumentation) MarshalJSON() ([]byte, error) {
+	return marshalJSONExtensions(externalDocumentationFields(e), e.Extensions)
+}
+
+func (e ExternalDocumentation) MarshalYAML() (interface{}, error) {
+	return marshalYAMLExtensions(externalDocumentationFields(e), e.Extensions)
+}
+
+func (e *ExternalDocumentation) UnmarshalJSON(data []byte) error {
+	return unmarshalJSONExtensions(data, (*externalDocumentationFields)(e), &e.Extensions)
+}
+
+func (e *ExternalDocumentation) UnmarshalYAML(value *yaml.Node) error {
+	return unmarshalYAMLExtensions(value, (*externalDocumentationFields)(e), &e.Extensions)
+}
+
+type securitySchemeFields SecurityScheme
+
+func (s SecurityScheme) MarshalJSON() ([]byte, error) {
+	return marshalJSONExtensions(securitySchemeFields(s), s.Extensions)
+}
+
+func (s SecurityScheme) MarshalYAML() (interface{}, error) {
+	return marshalYAMLExtensions(securitySchemeFields(s), s.Extensions)
+}
+
+func (s *SecurityScheme) UnmarshalJSON(data []byte) error {
+	return unmarshalJSONExtensions(data, (*securitySchemeFields)(s), &s.Extensions)
+}
+
+func (s *SecurityScheme) UnmarshalYAML(value *yaml.Node) error {
+	return unmarshalYAMLExtensions(value, (*securitySchemeFields)(s), &s.Extensions)
+}
+
+type oAuthFlowsFields OAuthFlows
+
+func (f OAuthFlows) MarshalJSON() ([]byte, error) {
+	return marshalJSONExtensions(oAuthFlowsFields(f), f.Extensions)
+}
+
+func (f OAuthFlows) MarshalYAML() (interface{}, error) {
+	return marshalYAMLExtensions(oAuthFlowsFields(f), f.Extensions)
+}
+
+func (f *OAuthFlows) UnmarshalJSON(data []byte) error {
+	return unmarshalJSONExtensions(data, (*oAuthFlowsFields)(f), &f.Extensions)
+}
+
+func (f *OAuthFlows) UnmarshalYAML(value *yaml.Node) error {
+	return unmarshalYAMLExtensions(value, (*oAuthFlowsFields)(f), &f.Extensions)
+}
+
+type oAuthFlowFields OAuthFlow
+
+func (f OAuthFlow) MarshalJSON() ([]byte, error) {
+	return marshalJSONExtensions(oAuthFlowFields(f), f.Extensions)
+}
+
+func (f OAuthFlow) MarshalYAML() (interface{}, error) {
+	return marshalYAMLExtensions(oAuthFlowFields(f), f.Extensions)
+}
+
+func (f *OAuthFlow) UnmarshalJSON(data []byte) error {
+	return unmarshalJSONExtensions(data, (*oAuthFlowFields)(f), &f.Extensions)
+}
+
+func (f *OAuthFlow) UnmarshalYAML(value *yaml.Node) error {
+	return unmarshalYAMLExtensions(value, (*oAuthFlowFields)(f), &f.Extensions)
+}
+
+type linkFields Link
+
+func (l Link) MarshalJSON() ([]byte, error) {
+	return marshalJSONExtensions(linkFields(l), l.Extensions)
+}
+
+func (l Link) MarshalYAML() (interface{}, error) {
+	return marshalYAMLExtensions(linkFields(l), l.Extensions)
+}
+
+func (l *Link) UnmarshalJSON(data []byte) error {
+	return unmarshalJSONExtensions(data, (*linkFields)(l), &l.Extensions)
+}
+
+func (l *Link) UnmarshalYAML(value *yaml.Node) error {
+	return unmarshalYAMLExtensions(value, (*linkFields)(l), &l.Extensions)
+}
+
+type xmlFields XML
+
+func (x XML) MarshalJSON() ([]byte, error) {
+	return marshalJSONExtensions(xmlFields(x), x.Extensions)
+}
+
+func (x XML) MarshalYAML() (interface{}, error) {
+	return marshalYAMLExtensions(xmlFields(x), x.Extensions)
+}
+
+func (x *XML) UnmarshalJSON(data []byte) error {
+	return unmarshalJSONExtensions(data, (*xmlFields)(x), &x.Extensions)
+}
+
+func (x *XML) UnmarshalYAML(value *yaml.Node) error {
+	return unmarshalYAMLExtensions(value, (*xmlFields)(x), &x.Extensions)
+}
+
+type encodingFields Encoding
+
+func (e Encoding) MarshalJSON() ([]byte, error) {
+	return marshalJSONExtensions(encodingFields(e), e.Extensions)
+}
+
+func (e Encoding) MarshalYAML() (interface{}, error) {
+	return marshalYAMLExtensions(encodingFields(e), e.Extensions)
+}
+
+func (e *Encoding) UnmarshalJSON(data []byte) error {
+	return unmarshalJSONExtensions(data, (*encodingFields)(e), &e.Extensions)
+}
+
+func (e *Encoding) UnmarshalYAML(value *yaml.Node) error {
+	return unmarshalYAMLExtensions(value, (*encodingFields)(e), &e.Extensions)
+}
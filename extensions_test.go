@@ -0,0 +1,105 @@
+package knife4g
+
+import (
+	"encoding/json"
+	"os"
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+// TestRoundTrip 将 testdata 中的文档解码为模型后重新编码，结果须与源文件逐字节一致：
+// 各层级的 x- 扩展字段不丢失，paths、responses、headers、content 与 properties 保持源文件顺序
+func TestRoundTrip(t *testing.T) {
+	tests := []struct {
+		file      string
+		unmarshal func([]byte, any) error
+		marshal   func(any) ([]byte, error)
+	}{
+		{"testdata/roundtrip.yaml", yaml.Unmarshal, yaml.Marshal},
+		{"testdata/roundtrip.json", json.Unmarshal, func(v any) ([]byte, error) {
+			data, err := json.MarshalIndent(v, "", "  ")
+			return append(data, '\n'), err
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			data, err := os.ReadFile(tt.file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var doc OpenAPI3
+			if err := tt.unmarshal(data, &doc); err != nil {
+				t.Fatal(err)
+			}
+			checkRoundTripModel(t, &doc)
+
+			got, err := tt.marshal(&doc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != string(data) {
+				t.Errorf("round trip differs from %s:\n%s", tt.file, got)
+			}
+		})
+	}
+}
+
+// checkRoundTripModel 校验解码后的模型中各层级的扩展字段与源文件顺序
+func checkRoundTripModel(t *testing.T, doc *OpenAPI3) {
+	t.Helper()
+	item := doc.Paths["/orders/{id}"]
+	get := item.Get
+	ok := get.Responses["200"]
+	order := doc.Components.Schemas["Order"]
+
+	extensions := []struct {
+		name string
+		ext  Extensions
+		key  string
+		want any
+	}{
+		{"document", doc.Extensions, "x-tenant", "shop"},
+		{"info", doc.Info.Extensions, "x-audience", "partners"},
+		{"server", doc.Servers[0].Extensions, "x-region", "eu"},
+		{"tag", doc.Tags[0].Extensions, ExtOrder, 1},
+		{"path item", item.Extensions, "x-group", "orders"},
+		{"operation", get.Extensions, ExtAuthor, "alice"},
+		{"parameter", get.Parameters[0].Extensions, "x-example-id", "o-1"},
+		{"request body", doc.Paths["/orders"].Post.RequestBody.Extensions, "x-body", "full"},
+		{"response", ok.Extensions, "x-cache", "60s"},
+		{"response false value", get.Responses["404"].Extensions, "x-retry", false},
+		{"header", ok.Headers["X-Rate-Limit"].Extensions, "x-unit", "requests"},
+		{"media type", ok.Content[MIMEApplicationJSON].Extensions, "x-preferred", true},
+		{"components", doc.Components.Extensions, "x-components", "shared"},
+		{"schema", order.Extensions, "x-entity", "order"},
+		{"property", order.Properties["total"].Extensions, "x-currency", "EUR"},
+	}
+	for _, tt := range extensions {
+		got, _ := tt.ext.Get(tt.key)
+		if n, isFloat := got.(float64); isFloat {
+			got = int(n)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s %s = %#v, want %#v", tt.name, tt.key, got, tt.want)
+		}
+	}
+
+	orders := []struct {
+		name      string
+		got, want []string
+	}{
+		{"paths", doc.OrderedPaths(), []string{"/orders/{id}", "/orders"}},
+		{"responses", get.OrderedResponses(), []string{"404", "200"}},
+		{"headers", ok.OrderedHeaders(), []string{"X-Rate-Limit", "ETag"}},
+		{"content", ok.OrderedContentTypes(), []string{"application/xml", MIMEApplicationJSON}},
+		{"schemas", doc.Components.OrderedSchemas(), []string{"Order", "Item"}},
+		{"properties", order.OrderedProperties(), []string{"total", "id", "items"}},
+	}
+	for _, tt := range orders {
+		if !reflect.DeepEqual(tt.got, tt.want) {
+			t.Errorf("%s order = %q, want %q", tt.name, tt.got, tt.want)
+		}
+	}
+}
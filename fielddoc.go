@@ -0,0 +1,140 @@
+package knife4g
+
+import (
+	"strconv"
+	"strings"
+)
+
+// NewFieldDoc 解析字段或参数描述中的注释标注
+func NewFieldDoc(description string) *FieldDoc {
+	parser := NewCommentParser().Parse(description)
+	doc := &FieldDoc{
+		FieldDescription: parser.GetString(TagDescription),
+		FieldExample:     parser.GetString("example"),
+		FieldFormat:      parser.GetString("format"),
+		FieldRequired:    parser.GetBool("required"),
+		FieldMinLength:   int(parser.GetNumber("minLength")),
+		FieldMaxLength:   int(parser.GetNumber("maxLength")),
+		FieldMinimum:     int(parser.GetNumber("minimum")),
+		FieldMaximum:     int(parser.GetNumber("maximum")),
+		FieldPattern:     strings.Trim(parser.GetString("pattern"), "\""),
+		Raw:              description,
+		FieldEnumValues:  parser.GetEnumValues(),
+		Annotations:      parser,
+	}
+	for _, v := range doc.FieldEnumValues {
+		n, err := strconv.Atoi(v.Value)
+		if err != nil {
+			doc.FieldEnum = nil
+			break
+		}
+		doc.FieldEnum = append(doc.FieldEnum, n)
+	}
+	return doc
+}
+
+// NewOperationDescription 解析接口描述中的注释标注
+func NewOperationDescription(description string) *OperationDescription {
+	parser := NewCommentParser()
+	doc := parser.ParseOperationDescription(description)
+	doc.Annotations = parser
+	return doc
+}
+
+// AnnotateDocument 返回文档副本，其中每个 Schema、接口、参数、响应头与分组的描述都已解析一次，
+// 结果分别写入 Schema.FieldDoc、Operation.Doc、Parameter.Doc、Header.Doc 与 Tag.Doc，原文档保持不变
+func AnnotateDocument(doc *OpenAPI3) *OpenAPI3 {
+	if doc == nil {
+		return nil
+	}
+
+	result := rewriteDocumentSchemas(doc, func(pointer string, schema *Schema) *Schema {
+		schema.FieldDoc = NewFieldDoc(schema.Description)
+		return schema
+	})
+
+	for path, item := range result.Paths {
+		annotateParameters(item.Parameters)
+		for _, op := range item.operations() {
+			op.operation.Doc = NewOperationDescription(op.operation.Description)
+			annotateParameters(op.operation.Parameters)
+			for _, resp := range op.operation.Responses {
+				annotateHeaders(resp.Headers)
+			}
+		}
+		result.Paths[path] = item
+	}
+	for name, param := range result.Components.Parameters {
+		param.Doc = NewFieldDoc(param.Description)
+		result.Components.Parameters[name] = param
+	}
+	for _, resp := range result.Components.Responses {
+		annotateHeaders(resp.Headers)
+	}
+	annotateHeaders(result.Components.Headers)
+
+	if result.Tags != nil {
+		tags := make([]Tag, len(result.Tags))
+		for i, tag := range result.Tags {
+			tag.Doc = NewFieldDoc(tag.Description)
+			tags[i] = tag
+		}
+		result.Tags = tags
+	}
+	return result
+}
+
+// annotateHeaders 就地填充响应头的注释解析结果，headers 须为文档副本中的映射
+func annotateHeaders(headers map[string]Header) {
+	for name, header := range headers {
+		header.Doc = NewFieldDoc(header.Description)
+		headers[name] = header
+	}
+}
+
+// annotateParameters 就地填充参数的注释解析结果，params 须为文档副本中的切片
+func annotateParameters(params []Parameter) {
+	for i := range params {
+		params[i].Doc = NewFieldDoc(params[i].Description)
+	}
+}
+
+// schemaAnnotations 返回 Schema 描述的解析结果，未经 AnnotateDocument 处理时即时解析
+func schemaAnnotations(schema *Schema) *CommentParser {
+	if schema.FieldDoc != nil && schema.FieldDoc.Annotations != nil {
+		return schema.FieldDoc.Annotations
+	}
+	return NewCommentParser().Parse(schema.Description)
+}
+
+// operationAnnotations 返回接口描述的解析结果，未经 AnnotateDocument 处理时即时解析
+func operationAnnotations(op *Operation) *CommentParser {
+	if op.Doc != nil && op.Doc.Annotations != nil {
+		return op.Doc.Annotations
+	}
+	return NewCommentParser().Parse(op.Description)
+}
+
+// parameterAnnotations 返回参数描述的解析结果，未经 AnnotateDocument 处理时即时解析
+func parameterAnnotations(param *Parameter) *CommentParser {
+	if param.Doc != nil && param.Doc.Annotations != nil {
+		return param.Doc.Annotations
+	}
+	return NewCommentParser().Parse(param.Description)
+}
+
+// headerAnnotations 返回响应头描述的解析结果，未经 AnnotateDocument 处理时即时解析
+func headerAnnotations(header *Header) *CommentParser {
+	if header.Doc != nil && header.Doc.Annotations != nil {
+		return header.Doc.Annotations
+	}
+	return NewCommentParser().Parse(header.Description)
+}
+
+// tagAnnotations 返回分组描述的解析结果，未经 AnnotateDocument 处理时即时解析
+func tagAnnotations(tag *Tag) *CommentParser {
+	if tag.Doc != nil && tag.Doc.Annotations != nil {
+		return tag.Doc.Annotations
+	}
+	return NewCommentParser().Parse(tag.Description)
+}
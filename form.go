@@ -0,0 +1,148 @@
+package knife4g
+
+import "strings"
+
+// isFormContentType 判断是否为以表单提交的请求体媒体类型
+func isFormContentType(contentType string) bool {
+	return contentType == MIMEMultipartFormData || contentType == MIMEFormURLEncoded
+}
+
+// convertFormMediaType 将表单媒体类型转换为 OpenAPI 3.0 格式。
+// Knife4j 调试面板只读取请求体 Schema 内联的 properties 逐个渲染表单字段（format: binary 渲染为文件选择），
+// 并把对象类型的字段视为 JSON 请求体，因此 $ref 与 allOf 被解开合并为内联的 properties，
+// 嵌套对象展开为 "parent.child" 形式的字段，encoding 中声明了 contentType 的字段作为整体提交
+func convertFormMediaType(media *MediaType, componentsSchemas map[string]Schema, parser *CommentParser) *orderedMap {
+	result := newOrderedMap()
+	if media.Schema != nil {
+		form := &formSchema{properties: newOrderedMap(), components: componentsSchemas, encoding: media.Encoding}
+		form.flatten("", media.Schema, true, make(map[string]bool))
+		schemaMap := newOrderedMap()
+		schemaMap.Set("type", "object")
+		if len(form.required) > 0 {
+			schemaMap.Set("required", form.required)
+		}
+		schemaMap.Set("properties", form.properties)
+		result.Set("schema", schemaMap)
+	}
+	if media.Example != nil {
+		result.Set("example", media.Example)
+	}
+	if len(media.Examples) > 0 {
+		result.Set("examples", media.Examples)
+	}
+	setExamples(result, parser, media.Schema, componentsSchemas)
+	if len(media.Encoding) > 0 {
+		encoding := newOrderedMap()
+		for _, name := range sortedKeys(media.Encoding) {
+			part := media.Encoding[name]
+			encoding.Set(name, convertEncodingToOpenAPI3(&part, componentsSchemas))
+		}
+		result.Set("encoding", encoding)
+	}
+	copyExtensions(result, media.Extensions)
+	return result
+}
+
+// formSchema 收集展开后的表单字段
+type formSchema struct {
+	properties *orderedMap
+	required   []string
+	components map[string]Schema
+	encoding   map[string]Encoding
+}
+
+// flatten 将对象 Schema 的属性按源文件顺序写入表单字段，allOf 成员的属性按成员顺序排在自身属性之前。
+// prefix 为上层字段名，required 表示上层字段是否必填，visiting 用于避免循环引用导致的无限递归
+func (f *formSchema) flatten(prefix string, schema *Schema, required bool, visiting map[string]bool) {
+	if schema.Ref != "" {
+		visiting[schema.Ref] = true
+		defer delete(visiting, schema.Ref)
+	}
+	schema = f.resolve(schema)
+
+	for _, member := range schema.AllOf {
+		if member != nil && !visiting[member.Ref] {
+			f.flatten(prefix, member, required, visiting)
+		}
+	}
+
+	requiredSet := make(map[string]bool, len(schema.Required))
+	for _, name := range schema.Required {
+		requiredSet[name] = true
+	}
+	for _, name := range schema.OrderedProperties() {
+		prop := schema.Properties[name]
+		if prop == nil {
+			continue
+		}
+		field := prefix + name
+		fieldRequired := required && requiredSet[name]
+		if f.hasFields(prop, make(map[string]bool)) && !visiting[prop.Ref] && f.encoding[field].ContentType == "" {
+			f.flatten(field+".", prop, fieldRequired, visiting)
+			continue
+		}
+		f.properties.Set(field, convertSchemaToOpenAPI3(prop, f.components))
+		if fieldRequired {
+			f.required = append(f.required, field)
+		}
+	}
+}
+
+// hasFields 判断 Schema 解开 $ref 后是否带有可展开的属性，包括 allOf 成员中的属性，
+// seen 记录已检查的 $ref 以避免循环引用
+func (f *formSchema) hasFields(schema *Schema, seen map[string]bool) bool {
+	if schema.Ref != "" {
+		if seen[schema.Ref] {
+			return false
+		}
+		seen[schema.Ref] = true
+	}
+	schema = f.resolve(schema)
+	if len(schema.Properties) > 0 {
+		return true
+	}
+	for _, member := range schema.AllOf {
+		if member != nil && f.hasFields(member, seen) {
+			return true
+		}
+	}
+	return false
+}
+
+// resolve 解开指向 components.schemas 的 $ref，无法解开时返回原 Schema
+func (f *formSchema) resolve(schema *Schema) *Schema {
+	if !strings.HasPrefix(schema.Ref, componentsSchemasPrefix) {
+		return schema
+	}
+	if component, exists := f.components[strings.TrimPrefix(schema.Ref, componentsSchemasPrefix)]; exists {
+		return &component
+	}
+	return schema
+}
+
+// convertEncodingToOpenAPI3 将表单字段的编码声明转换为 OpenAPI 3.0 格式
+func convertEncodingToOpenAPI3(encoding *Encoding, componentsSchemas map[string]Schema) *orderedMap {
+	result := newOrderedMap()
+	if encoding.ContentType != "" {
+		result.Set("contentType", encoding.ContentType)
+	}
+	if len(encoding.Headers) > 0 {
+		headers := newOrderedMap()
+		for _, name := range sortedKeys(encoding.Headers) {
+			header := encoding.Headers[name]
+			headers.Set(name, convertHeaderToOpenAPI3(&header, componentsSchemas))
+		}
+		result.Set("headers", headers)
+	}
+	if encoding.Style != "" {
+		result.Set("style", encoding.Style)
+	}
+	if encoding.Explode {
+		result.Set("explode", true)
+	}
+	if encoding.AllowReserved {
+		result.Set("allowReserved", true)
+	}
+	copyExtensions(result, encoding.Extensions)
+	return result
+}
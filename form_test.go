@@ -0,0 +1,113 @@
+package knife4g
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestConvertFormMediaType(t *testing.T) {
+	base := Schema{Type: SchemaType{"object"}, Required: []string{"id"}}
+	base.SetProperty("id", stringSchema())
+	base.SetProperty("createdAt", &Schema{Type: SchemaType{"string"}, Format: "date-time"})
+
+	address := Schema{Type: SchemaType{"object"}, Required: []string{"city"}}
+	address.SetProperty("city", stringSchema())
+	address.SetProperty("zip", stringSchema())
+
+	node := Schema{Type: SchemaType{"object"}}
+	node.SetProperty("name", stringSchema())
+	node.SetProperty("parent", refSchema("Node"))
+
+	components := map[string]Schema{"Base": base, "Address": address, "Node": node}
+
+	meta := objectSchema(map[string]*Schema{"source": stringSchema()})
+	request := &Schema{
+		Type:     SchemaType{"object"},
+		AllOf:    []*Schema{refSchema("Base")},
+		Required: []string{"name", "address"},
+	}
+	request.SetProperty("name", stringSchema())
+	request.SetProperty("address", refSchema("Address"))
+	request.SetProperty("billing", refSchema("Address"))
+	request.SetProperty("avatar", &Schema{Type: SchemaType{"string"}, Format: ParamFormatBinary})
+	request.SetProperty("meta", meta)
+	request.SetProperty("node", refSchema("Node"))
+
+	media := &MediaType{
+		Schema:   request,
+		Encoding: map[string]Encoding{"meta": {ContentType: MIMEApplicationJSON}, "avatar": {ContentType: "image/png"}},
+	}
+	result := convertFormMediaType(media, components, NewCommentParser().Parse(""))
+
+	schema := orderedLookup(result, "schema")
+	properties := orderedLookup(schema, "properties")
+	wantFields := []string{
+		"id", "createdAt", "name", "address.city", "address.zip", "billing.city", "billing.zip",
+		"avatar", "meta", "node.name", "node.parent",
+	}
+	if !reflect.DeepEqual(properties.keys, wantFields) {
+		t.Errorf("fields = %q, want %q", properties.keys, wantFields)
+	}
+	required, _ := schema.Get("required")
+	if want := []string{"id", "name", "address.city"}; !reflect.DeepEqual(required, want) {
+		t.Errorf("required = %q, want %q", required, want)
+	}
+
+	fields := map[string]string{
+		"avatar":      `{"type":"string","format":"binary"}`,
+		"meta":        `{"type":"object","properties":{"source":{"type":"string"}}}`,
+		"node.parent": `{"$ref":"#/components/schemas/Node"}`,
+	}
+	for name, want := range fields {
+		data, err := json.Marshal(properties.values[name])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != want {
+			t.Errorf("%s = %s, want %s", name, data, want)
+		}
+	}
+
+	data, err := json.Marshal(orderedLookup(result, "encoding"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"avatar":{"contentType":"image/png"},"meta":{"contentType":"application/json"}}`; string(data) != want {
+		t.Errorf("encoding = %s, want %s", data, want)
+	}
+}
+
+func TestFormRequestBody(t *testing.T) {
+	upload := &Schema{Type: SchemaType{"object"}, Required: []string{"file"}}
+	upload.SetProperty("file", &Schema{Type: SchemaType{"string"}, Format: ParamFormatBinary})
+	upload.SetProperty("owner", refSchema("Owner"))
+	owner := *objectSchema(map[string]*Schema{"name": stringSchema()})
+
+	doc := &OpenAPI3{
+		OpenAPI: "3.0.3",
+		Info:    Info{Title: "t", Version: "1"},
+		Paths: map[string]PathItem{
+			"/upload": {Post: &Operation{
+				RequestBody: &RequestBody{Content: map[string]MediaType{
+					MIMEMultipartFormData: {Schema: upload},
+					MIMEFormURLEncoded:    {Schema: refSchema("Owner")},
+					MIMEApplicationJSON:   {Schema: refSchema("Owner")},
+				}},
+				Responses: map[string]Response{"200": {Description: "OK"}},
+			}},
+		},
+		Components: Components{Schemas: map[string]Schema{"Owner": owner}},
+	}
+	content := lookup(serveJSON(t, &Config{OpenAPI: doc}, "/v3/api-docs"), "paths", "/upload", "post", "requestBody", "content")
+
+	if got := keysOf(lookup(content, MIMEMultipartFormData, "schema", "properties")); !reflect.DeepEqual(got, map[string]bool{"file": true, "owner.name": true}) {
+		t.Errorf("multipart fields = %v", got)
+	}
+	if got := keysOf(lookup(content, MIMEFormURLEncoded, "schema", "properties")); !reflect.DeepEqual(got, map[string]bool{"name": true}) {
+		t.Errorf("urlencoded fields = %v", got)
+	}
+	if got := lookup(content, MIMEApplicationJSON, "schema", "$ref"); got != "#/components/schemas/Owner" {
+		t.Errorf("json body schema = %v, want $ref to Owner", got)
+	}
+}
@@ -1,6 +1,8 @@
 package knife4g
 
 import (
+	"bytes"
+	"compress/gzip"
 	"embed"
 	"encoding/json"
 	"fmt"
@@ -8,29 +10,54 @@ import (
 	"io/fs"
 	"log"
 	"log/slog"
+	"mime"
 	"net/http"
 	"path/filepath"
+	"strconv"
 	"strings"
+
+	"gopkg.in/yaml.v3"
 )
 
 const (
 	// MIME 媒体类型常量
 	MIMEApplicationJSON   = "application/json"
+	MIMEApplicationYAML   = "application/yaml"
 	MIMEMultipartFormData = "multipart/form-data"
 
+	MIMEFormURLEncoded         = "application/x-www-form-urlencoded"
+	MIMEApplicationOctetStream = "application/octet-stream"
+
 	// OpenAPI Parameter 位置与数据类型常量
 	ParamInFormData   = "formData"
+	ParamInHeader     = "header"
+	ParamInCookie     = "cookie"
+	ParamInQuery      = "query"
+	ParamInPath       = "path"
 	ParamTypeFile     = "file"
 	ParamTypeString   = "string"
 	ParamFormatBinary = "binary"
 
 	// Knife4g 注释扩展 Key 常量
 	TagConsumes    = "consumes"
+	TagProduces    = "produces"
 	TagFile        = "file"
 	TagDescription = "description"
 	TagSummary     = "summary"
 	TagOperationID = "operationId"
 	TagTags        = "tags"
+	TagOrder       = "order"
+	TagDeprecated  = "deprecated"
+	TagHidden      = "hidden"
+	TagInternal    = "internal"
+	TagParam       = "param"
+	TagRouter      = "router"
+)
+
+// 文档输出格式
+const (
+	docFormatJSON = "json"
+	docFormatYAML = "yaml"
 )
 
 var (
@@ -39,16 +66,41 @@ var (
 )
 
 type Config struct {
-	RelativePath  string // 访问前缀，如 "/doc"
-	ServerName    string // 服务名称
-	OpenAPI       *OpenAPI3
-	SwagResources []*SwaggerResource
+	RelativePath    string    // 访问前缀，如 "/doc"
+	ServerName      string    // 服务名称
+	OpenAPI         *OpenAPI3 // 在创建 Handler 时完成降级与注释解析，之后不应再修改
+	SwagResources   []*SwaggerResource
+	EnableSwagger2  bool                // 是否额外提供 /v2/api-docs（Swagger 2.0 格式）
+	AnnotationCheck AnnotationCheckMode // 启动时对注释标注的检查方式，默认不检查
+
+	// GlobalParameters 追加到每个接口的全局参数，如鉴权头、链路追踪 ID，In 为空时视为 header；
+	// 接口自身或 @header/@cookie/@query 标注声明的同名参数优先
+	GlobalParameters []Parameter
+
+	// InternalViewer 判断请求方能否查看 @internal 标注的接口、参数与字段，为空时这些内容对所有请求隐藏
+	InternalViewer func(r *http.Request) bool
+
+	// Compact 输出精简文档：省略空的 summary/description/operationId、null、空列表与取缺省值 false 的字段
+	Compact bool
+
+	// Gzip 请求方支持 gzip 时压缩 /v3/api-docs 与 /v2/api-docs 响应
+	Gzip bool
 }
 
+// AnnotationCheckMode 注释标注检查模式
+type AnnotationCheckMode int
+
+const (
+	AnnotationCheckOff    AnnotationCheckMode = iota // 不检查
+	AnnotationCheckWarn                              // 通过 slog 输出警告
+	AnnotationCheckStrict                            // 存在问题时 NewKnife4jServer 返回 *AnnotationError
+)
+
 // Knife4jServer Knife4j服务器结构
 type Knife4jServer struct {
 	config   *Config
 	staticFS fs.FS
+	document *OpenAPI3 // 创建时降级为 3.0 并解析注释的文档，请求时只需按访问者过滤可见性
 }
 
 // SwaggerResource 表示 Swagger 资源信息
@@ -89,9 +141,15 @@ func Handler(config *Config) http.Handler {
 		slog.Debug("处理请求", "path", path)
 
 		switch path {
-		case "/v3/api-docs":
+		case "/v3/api-docs", "/v3/api-docs.json", "/v3/api-docs.yaml", "/v3/api-docs.yml":
+			server.handleOpenAPIDocs(w, r, negotiateDocFormat(r, path))
+		case "/v2/api-docs":
+			if !config.EnableSwagger2 {
+				http.NotFound(w, r)
+				return
+			}
 			w.Header().Set("Content-Type", "application/json")
-			server.handleOpenAPIDocs(w, r)
+			server.handleSwagger2Docs(w, r)
 		case "/v3/api-docs/swagger-config":
 			w.Header().Set("Content-Type", "application/json")
 			server.handleSwaggerConfig(w, r)
@@ -136,28 +194,211 @@ func NewKnife4jServer(cfg *Config) (*Knife4jServer, error) {
 		cfg.SwagResources = defaultResources
 	}
 
+	// 降级与注释解析只在创建时进行一次，OpenAPI 3.1 文档启动时提示无法映射到 3.0 的内容
+	var document *OpenAPI3
+	if cfg.OpenAPI != nil {
+		downgraded, warnings := DowngradeToOpenAPI30(cfg.OpenAPI)
+		if cfg.OpenAPI.IsOpenAPI31() {
+			for _, warning := range warnings {
+				slog.Warn("OpenAPI 3.1 内容无法映射到 3.0", "pointer", warning.Pointer, "reason", warning.Message)
+			}
+		}
+		document = AnnotateDocument(downgraded)
+	}
+
+	// Swagger 2.0 无法表达多媒体类型等常见内容，只在调试级别汇总输出一条日志
+	if cfg.EnableSwagger2 && document != nil {
+		annotated, err := annotatedOpenAPI3(convertToOpenAPI3(document, cfg, true))
+		if err != nil {
+			return nil, fmt.Errorf("failed to apply comment annotations: %v", err)
+		}
+		if _, warnings, err := ExportSwagger2(annotated); err == nil && len(warnings) > 0 {
+			slog.Debug("部分 OpenAPI 内容无法映射到 Swagger 2.0", "count", len(warnings), "warnings", warnings)
+		}
+	}
+
+	// 检查注释标注中的格式错误、非法取值与未知标注
+	if cfg.AnnotationCheck != AnnotationCheckOff && cfg.OpenAPI != nil {
+		if diagnostics := CollectAnnotationDiagnostics(cfg.OpenAPI); len(diagnostics) > 0 {
+			if cfg.AnnotationCheck == AnnotationCheckStrict {
+				return nil, &AnnotationError{Diagnostics: diagnostics}
+			}
+			for _, d := range diagnostics {
+				slog.Warn("注释标注有误", "pointer", d.Pointer, "tag", d.Tag, "raw", d.Raw, "reason", d.Reason)
+			}
+		}
+	}
+
 	server := &Knife4jServer{
 		config:   cfg,
 		staticFS: subFS,
+		document: document,
 	}
 	return server, nil
 }
 
-// handleOpenAPIDocs 处理 OpenAPI 文档请求
-func (s *Knife4jServer) handleOpenAPIDocs(w http.ResponseWriter, r *http.Request) {
+// handleOpenAPIDocs 处理 OpenAPI 文档请求，?raw=true 时输出未经注释处理的 Config.OpenAPI，
+// 但仍会去除 @hidden 内容，以及当前请求无权查看的 @internal 内容
+func (s *Knife4jServer) handleOpenAPIDocs(w http.ResponseWriter, r *http.Request, format string) {
 	if s.config.OpenAPI == nil {
 		http.Error(w, "OpenAPI document not loaded", http.StatusInternalServerError)
 		return
 	}
 
-	openAPI3 := convertToOpenAPI3(s.config.OpenAPI, s.config)
-	w.Header().Set("Content-Type", "application/json")
+	// @hidden 与 @internal 内容在原始文档中同样被去除
+	var doc any
+	if raw, _ := strconv.ParseBool(r.URL.Query().Get("raw")); raw {
+		doc = filterVisibility(s.config.OpenAPI, s.showInternal(r))
+	} else {
+		result := convertToOpenAPI3(s.document, s.config, s.showInternal(r))
+		if s.config.Compact {
+			compactDocument(result)
+		}
+		doc = result
+	}
 	s.setCORSHeaders(w)
 
-	if err := json.NewEncoder(w).Encode(openAPI3); err != nil {
+	// 先完整编码再写出，编码失败时仍可返回错误而不会留下写了一半的响应
+	var buf bytes.Buffer
+	contentType := MIMEApplicationJSON
+	var err error
+	if format == docFormatYAML {
+		contentType = MIMEApplicationYAML
+		encoder := yaml.NewEncoder(&buf)
+		encoder.SetIndent(2)
+		if err = encoder.Encode(doc); err == nil {
+			err = encoder.Close()
+		}
+	} else {
+		err = json.NewEncoder(&buf).Encode(doc)
+	}
+	if err != nil {
 		slog.Debug("Failed to encode OpenAPI document", "err", err)
 		http.Error(w, "Failed to encode OpenAPI document", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", contentType)
+	s.writeDocument(w, r, buf.Bytes())
+}
+
+// writeDocument 写出已编码的文档，开启 Gzip 且请求方支持时压缩响应
+func (s *Knife4jServer) writeDocument(w http.ResponseWriter, r *http.Request, data []byte) {
+	if !s.config.Gzip {
+		w.Write(data)
+		return
+	}
+	w.Header().Add("Vary", "Accept-Encoding")
+	if !acceptsGzip(r) {
+		w.Write(data)
+		return
+	}
+	w.Header().Set("Content-Encoding", "gzip")
+	gz := gzip.NewWriter(w)
+	if _, err := gz.Write(data); err != nil {
+		slog.Debug("Failed to write compressed document", "err", err)
+	}
+	gz.Close()
+}
+
+// acceptsGzip 判断请求的 Accept-Encoding 是否接受 gzip
+func acceptsGzip(r *http.Request) bool {
+	for _, part := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if !strings.EqualFold(strings.TrimSpace(coding), "gzip") {
+			continue
+		}
+		q, found := strings.CutPrefix(strings.TrimSpace(params), "q=")
+		if !found {
+			return true
+		}
+		v, err := strconv.ParseFloat(strings.TrimSpace(q), 64)
+		return err == nil && v > 0
+	}
+	return false
+}
+
+// showInternal 判断当前请求能否查看 @internal 标注的内容
+func (s *Knife4jServer) showInternal(r *http.Request) bool {
+	return s.config.InternalViewer != nil && s.config.InternalViewer(r)
+}
+
+// negotiateDocFormat 根据路径扩展名、?format= 参数以及 Accept 头决定文档输出格式，默认 JSON
+func negotiateDocFormat(r *http.Request, path string) string {
+	switch filepath.Ext(path) {
+	case ".yaml", ".yml":
+		return docFormatYAML
+	case ".json":
+		return docFormatJSON
+	}
+	switch strings.ToLower(r.URL.Query().Get("format")) {
+	case "yaml", "yml":
+		return docFormatYAML
+	case "json":
+		return docFormatJSON
+	}
+
+	// 按 q 值选择 Accept 中优先级最高的 JSON/YAML 媒体类型
+	format, best := docFormatJSON, -1.0
+	for _, part := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if v, err := strconv.ParseFloat(params["q"], 64); err == nil {
+			q = v
+		}
+		candidate := ""
+		switch {
+		case strings.Contains(mediaType, "yaml"):
+			candidate = docFormatYAML
+		case strings.Contains(mediaType, "json"):
+			candidate = docFormatJSON
+		}
+		if candidate != "" && q > best {
+			format, best = candidate, q
+		}
+	}
+	return format
+}
+
+// handleSwagger2Docs 处理 Swagger 2.0 文档请求
+func (s *Knife4jServer) handleSwagger2Docs(w http.ResponseWriter, r *http.Request) {
+	if s.config.OpenAPI == nil {
+		http.Error(w, "OpenAPI document not loaded", http.StatusInternalServerError)
+		return
+	}
+
+	// 与 /v3/api-docs 一样先应用注释标注，两种格式输出相同的内容
+	doc, err := annotatedOpenAPI3(convertToOpenAPI3(s.document, s.config, s.showInternal(r)))
+	if err != nil {
+		slog.Debug("Failed to export Swagger 2.0 document", "err", err)
+		http.Error(w, "Failed to export Swagger 2.0 document", http.StatusInternalServerError)
+		return
+	}
+	data, err := ToSwagger2(doc)
+	if err != nil {
+		slog.Debug("Failed to export Swagger 2.0 document", "err", err)
+		http.Error(w, "Failed to export Swagger 2.0 document", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	s.setCORSHeaders(w)
+	s.writeDocument(w, r, data)
+}
+
+// annotatedOpenAPI3 将 convertToOpenAPI3 的输出读回 OpenAPI3，得到已应用注释标注的文档，
+// 供 Swagger 2.0 导出等基于结构体的处理使用
+func annotatedOpenAPI3(converted *orderedMap) (*OpenAPI3, error) {
+	data, err := json.Marshal(converted)
+	if err != nil {
+		return nil, err
+	}
+	doc := &OpenAPI3{}
+	if err := json.Unmarshal(data, doc); err != nil {
+		return nil, err
 	}
+	return doc, nil
 }
 
 // handleSwaggerConfig 处理 Swagger 配置请求
@@ -247,70 +488,75 @@ func (s *Knife4jServer) setContentType(w http.ResponseWriter, ext string) {
 	}
 }
 
-// convertToOpenAPI3 将 OpenAPI 对象转换为标准的 OpenAPI 3.0 JSON 结构
-func convertToOpenAPI3(openapi *OpenAPI3, config *Config) map[string]any {
-	result := make(map[string]any)
+// convertToOpenAPI3 将已降级为 3.0 并经 AnnotateDocument 处理的文档转换为标准的 OpenAPI 3.0 JSON 结构，
+// @hidden 内容始终去除，@internal 内容仅在 showInternal 为 true 时保留。
+// 输出对象按固定顺序写入键，properties、paths、responses 等保持源文件中的顺序，保证多次输出字节一致
+func convertToOpenAPI3(openapi *OpenAPI3, config *Config, showInternal bool) *orderedMap {
+	result := newOrderedMap()
+	openapi = filterVisibility(openapi, showInternal)
 
 	// 基本信息
 	if openapi.OpenAPI != "" {
-		result["openapi"] = openapi.OpenAPI
+		result.Set("openapi", openapi.OpenAPI)
 	} else {
-		result["openapi"] = "3.0.1"
+		result.Set("openapi", "3.0.1")
 	}
 
 	// 构建 info 对象
-	info := map[string]any{
-		"title":   openapi.Info.Title,
-		"version": openapi.Info.Version,
-		"name":    config.ServerName, // 服务名称
-	}
+	info := newOrderedMap()
+	info.Set("title", openapi.Info.Title)
+	info.Set("version", openapi.Info.Version)
+	info.Set("name", config.ServerName) // 服务名称
 
 	// 解析 info 的注释
 	infoParser := NewCommentParser().Parse(openapi.Info.Description)
 
 	// 从解析器中获取标签值
 	if infoParser.HasTag("description") {
-		info["description"] = infoParser.GetString("description")
+		info.Set("description", infoParser.GetString("description"))
 	}
 
-	result["info"] = info
+	copyExtensions(info, openapi.Info.Extensions)
+	result.Set("info", info)
 
 	// 处理 servers
 	if len(openapi.Servers) > 0 {
-		servers := make([]map[string]any, len(openapi.Servers))
+		servers := make([]*orderedMap, len(openapi.Servers))
 		for i, server := range openapi.Servers {
-			serverMap := map[string]any{
-				"url":         server.URL,
-				"description": server.Description,
-			}
+			serverMap := newOrderedMap()
+			serverMap.Set("url", server.URL)
+			serverMap.Set("description", server.Description)
 			if len(server.Variables) > 0 {
-				variables := make(map[string]any)
-				for name, variable := range server.Variables {
-					variables[name] = map[string]any{
-						"default":     variable.Default,
-						"description": variable.Description,
-						"enum":        variable.Enum,
-					}
+				variables := newOrderedMap()
+				for _, name := range sortedKeys(server.Variables) {
+					variable := server.Variables[name]
+					variableMap := newOrderedMap()
+					variableMap.Set("default", variable.Default)
+					variableMap.Set("description", variable.Description)
+					variableMap.Set("enum", variable.Enum)
+					variables.Set(name, variableMap)
 				}
-				serverMap["variables"] = variables
+				serverMap.Set("variables", variables)
 			}
+			copyExtensions(serverMap, server.Extensions)
 			servers[i] = serverMap
 		}
-		result["servers"] = servers
+		result.Set("servers", servers)
 	}
 
 	// 处理全局 tags 列表
 	if len(openapi.Tags) > 0 {
-		tags := make([]map[string]any, 0, len(openapi.Tags))
-		for _, tag := range openapi.Tags {
+		tags := make([]*orderedMap, 0, len(openapi.Tags))
+		for i, tag := range openapi.Tags {
 			tagName := tag.Name
 			tagDesc := tag.Description
+			tagOrder, hasOrder := tag.Extensions.Order()
 
 			// 解析 Service 节点注释文本
 			//（位于 tag.Description 中）
 			// 提取自定义的 @tags: 标签名与 @description: 描述信息
 			if tag.Description != "" {
-				parser := NewCommentParser().Parse(tag.Description)
+				parser := tagAnnotations(&tag)
 				if parser.HasTag("tags") {
 					if customTag := parser.GetString("tags"); customTag != "" {
 						// 使用 Proto 中定义的 Service 级 @tags 替换默认的服务 Tag 名称
@@ -320,6 +566,9 @@ func convertToOpenAPI3(openapi *OpenAPI3, config *Config) map[string]any {
 				if parser.HasTag("description") {
 					tagDesc = parser.GetString("description") // 提取过滤掉扩展标记后的纯文本描述
 				}
+				if parser.HasTag(TagOrder) {
+					tagOrder, hasOrder = int(parser.GetNumber(TagOrder)), true
+				}
 			} else {
 				// 若单proto服务编译时 tags description 为空，
 				// 解析 info 里的 tags 定义
@@ -328,389 +577,815 @@ func convertToOpenAPI3(openapi *OpenAPI3, config *Config) map[string]any {
 						tagName = customTag
 					}
 				}
+				if infoParser.HasTag(TagOrder) {
+					tagOrder, hasOrder = int(infoParser.GetNumber(TagOrder)), true
+				}
 			}
-
-			tagMap := map[string]any{
-				"name": tagName,
+			// 未指定 @order 时按 tags 在源文件中的顺序排序
+			if !hasOrder {
+				tagOrder = i + 1
 			}
+
+			tagMap := newOrderedMap()
+			tagMap.Set("name", tagName)
 			if tagDesc != "" {
-				tagMap["description"] = tagDesc
+				tagMap.Set("description", tagDesc)
 			}
 			if tag.ExternalDocs != nil {
-				tagMap["externalDocs"] = map[string]any{
-					"description": tag.ExternalDocs.Description,
-					"url":         tag.ExternalDocs.URL,
-				}
+				externalDocs := newOrderedMap()
+				externalDocs.Set("description", tag.ExternalDocs.Description)
+				externalDocs.Set("url", tag.ExternalDocs.URL)
+				tagMap.Set("externalDocs", externalDocs)
 			}
+			tagMap.Set(ExtOrder, tagOrder)
+			copyExtensions(tagMap, tag.Extensions)
 			tags = append(tags, tagMap)
 		}
-		result["tags"] = tags
+		result.Set("tags", tags)
 	}
 
-	// 处理 paths
-	paths := make(map[string]any)
-	for path, pathItem := range openapi.Paths {
-		pathMap := make(map[string]any)
+	// 处理 paths，按源文件顺序遍历以便为未指定 @order 的接口生成稳定的 x-order
+	paths := newOrderedMap()
+	operationIndex := 0
+	for _, path := range openapi.OrderedPaths() {
+		pathItem := openapi.Paths[path]
+		pathMap := newOrderedMap()
 
 		// 处理各种 HTTP 方法
-		if pathItem.Get != nil {
-			pathMap["get"] = convertOperationToOpenAPI3(pathItem.Get, openapi.Components.Schemas)
-		}
-		if pathItem.Post != nil {
-			pathMap["post"] = convertOperationToOpenAPI3(pathItem.Post, openapi.Components.Schemas)
-		}
-		if pathItem.Put != nil {
-			pathMap["put"] = convertOperationToOpenAPI3(pathItem.Put, openapi.Components.Schemas)
-		}
-		if pathItem.Delete != nil {
-			pathMap["delete"] = convertOperationToOpenAPI3(pathItem.Delete, openapi.Components.Schemas)
-		}
-		if pathItem.Patch != nil {
-			pathMap["patch"] = convertOperationToOpenAPI3(pathItem.Patch, openapi.Components.Schemas)
+		for _, op := range pathItem.operations() {
+			operationIndex++
+			// 路径级参数（如共用的 {id}）对该路径下的每个接口生效，接口自身的同名参数优先
+			inherited := append(append([]Parameter(nil), pathItem.Parameters...), config.GlobalParameters...)
+			opMap := convertOperationToOpenAPI3(op.operation, openapi.Components.Schemas, inherited)
+			if !opMap.Has(ExtOrder) {
+				opMap.Set(ExtOrder, operationIndex)
+			}
+			pathMap.Set(op.method, opMap)
 		}
 
-		paths[path] = pathMap
+		copyExtensions(pathMap, pathItem.Extensions)
+		paths.Set(path, pathMap)
 	}
-	result["paths"] = paths
+	result.Set("paths", paths)
 
 	// 处理 components
-	components := make(map[string]any)
-	components["schemas"] = convertSchemasToOpenAPI3(openapi.Components.Schemas)
-	result["components"] = components
+	components := newOrderedMap()
+	components.Set("schemas", convertSchemasToOpenAPI3(&openapi.Components))
+	copyExtensions(components, openapi.Components.Extensions)
+	result.Set("components", components)
 
+	copyExtensions(result, openapi.Extensions)
 	return result
 }
 
-// getTargetSchema 极简解开 Operation 请求体中 $ref 所引用的 Component Schema 节点
-func getTargetSchema(op *Operation, componentsSchemas map[string]Schema) *Schema {
-	if op.RequestBody == nil || op.RequestBody.Content == nil {
-		return nil
-	}
-	for _, media := range op.RequestBody.Content {
-		if media.Schema != nil {
-			if media.Schema.Ref != "" {
-				schemaName := filepath.Base(media.Schema.Ref)
-				if compSchema, exists := componentsSchemas[schemaName]; exists {
-					return &compSchema
-				}
-			} else {
-				return media.Schema
-			}
-		}
-	}
-	return nil
-}
-
 // convertOperationToOpenAPI3 将 Operation 转换为 OpenAPI 3.0 格式并解析注释扩展指令
-func convertOperationToOpenAPI3(op *Operation, componentsSchemas map[string]Schema) map[string]any {
-	result := make(map[string]any)
+func convertOperationToOpenAPI3(op *Operation, componentsSchemas map[string]Schema, globals []Parameter) *orderedMap {
+	result := newOrderedMap()
 
 	// 基本信息
-	result["tags"] = op.Tags
-	result["summary"] = op.Summary
-	result["operationId"] = op.OperationID
+	tags := op.Tags
+	summary := op.Summary
+	operationID := op.OperationID
 
 	// 使用注释解析器处理 RPC 操作的 description 文本
-	parser := NewCommentParser().Parse(op.Description)
-	if parser.HasTag(TagDescription) {
-		result["description"] = parser.GetString(TagDescription)
-	}
-	if (result["summary"] == nil || result["summary"] == "") && parser.HasTag(TagSummary) {
-		result["summary"] = parser.GetString(TagSummary)
+	parser := operationAnnotations(op)
+	if summary == "" && parser.HasTag(TagSummary) {
+		summary = parser.GetString(TagSummary)
 	}
-	if (result["operationId"] == nil || result["operationId"] == "") && parser.HasTag(TagOperationID) {
-		result["operationId"] = parser.GetString(TagOperationID)
+	if operationID == "" && parser.HasTag(TagOperationID) {
+		operationID = parser.GetString(TagOperationID)
 	}
 	if parser.HasTag(TagTags) && len(parser.GetArray(TagTags)) > 0 {
-		result["tags"] = parser.GetArray(TagTags)
+		tags = parser.GetArray(TagTags)
 	}
 
-	// 解开 $ref 追溯查找当前 Operation 请求体所引用的 Component Schema 节点
-	targetSchema := getTargetSchema(op, componentsSchemas)
-
-	// 根据 RPC 方法注释上的 @consumes 精准认定文件上传接口
-	consumesVal := parser.GetString(TagConsumes)
-	isFileOperation := consumesVal == MIMEMultipartFormData || parser.HasTag(TagFile)
-
-	if isFileOperation {
-		result["consumes"] = []string{MIMEMultipartFormData}
-		result["produces"] = []string{MIMEApplicationJSON}
-	} else if consumesVal != "" {
-		result["consumes"] = []string{consumesVal}
+	if len(tags) > 0 {
+		result.Set("tags", tags)
+	}
+	if summary != "" {
+		result.Set("summary", summary)
+	}
+	if parser.HasTag(TagDescription) {
+		result.Set("description", parser.GetString(TagDescription))
+	}
+	if operationID != "" {
+		result.Set("operationId", operationID)
+	}
+	if op.Deprecated || parser.GetBool(TagDeprecated) {
+		result.Set("deprecated", true)
+		appendDeprecation(result, parser.GetString(TagDeprecated))
 	}
 
-	// 处理请求体
+	// 请求体，@consumes 标注给出默认媒体类型，表单类型的媒体类型由 Schema 展开为表单字段
+	var requestBody *orderedMap
+	var requestTypes []string
+	hasForm := false
 	if op.RequestBody != nil {
-		requestBody := make(map[string]any)
-		requestBody["required"] = op.RequestBody.Required
-		contentMap := convertContentToOpenAPI3(op.RequestBody.Content)
-		requestBody["content"] = contentMap
-		// 对于文件上传接口，不向前端暴露 requestBody 节点，消除 Knife4j Vue 前端产生 in: "body" 并强制切换为 raw 的死锁
-		if !isFileOperation {
-			result["requestBody"] = requestBody
+		content, contentTypes := preferredContent(op.RequestBody.Content, op.RequestBody.OrderedContentTypes(), requestContentTypes(parser))
+		contentMap := newOrderedMap()
+		for _, contentType := range contentTypes {
+			mediaType := content[contentType]
+			if isFormContentType(contentType) {
+				hasForm = true
+				contentMap.Set(contentType, convertFormMediaType(&mediaType, componentsSchemas, parser))
+			} else {
+				contentMap.Set(contentType, convertMediaTypeToOpenAPI3(&mediaType, parser, componentsSchemas))
+			}
+		}
+		requestTypes = contentTypes
+		requestBody = newOrderedMap()
+		if op.RequestBody.Required {
+			requestBody.Set("required", true)
 		}
+		requestBody.Set("content", contentMap)
+		copyExtensions(requestBody, op.RequestBody.Extensions)
+	} else {
+		requestTypes = parser.GetArray(TagConsumes)
 	}
 
-	// 针对 Knife4j 前端 UI 调试面板展平注入 formData 属性，确保前端能 100% 渲染出文件上传选择控件与输入框
-	if isFileOperation && targetSchema != nil && len(targetSchema.Properties) > 0 {
-		reqSet := make(map[string]bool)
-		for _, reqField := range targetSchema.Required {
-			reqSet[reqField] = true
+	// consumes/produces 供 Knife4j 调试面板选择请求方式与展示响应类型，produces 含文件类型时调试面板以文件下载响应
+	produces := parser.GetArray(TagProduces)
+	if hasForm || parser.HasTag(TagConsumes) {
+		result.Set("consumes", requestTypes)
+	}
+	if responseTypes, binary := operationProduces(op, parser, produces); len(responseTypes) > 0 && (hasForm || binary || len(produces) > 0) {
+		result.Set("produces", responseTypes)
+	} else if hasForm {
+		result.Set("produces", []string{MIMEApplicationJSON})
+	}
+
+	if parameters := operationParameters(op, parser, globals); len(parameters) > 0 {
+		params := make([]*orderedMap, len(parameters))
+		for i := range parameters {
+			params[i] = convertParameterToOpenAPI3(&parameters[i], componentsSchemas)
 		}
+		result.Set("parameters", params)
+	}
+	if requestBody != nil {
+		result.Set("requestBody", requestBody)
+	}
 
-		formDataParams := make([]map[string]any, 0, len(targetSchema.Properties))
-		for propName, propSchema := range targetSchema.Properties {
-			pType := propSchema.Type
-			if pType == "" {
-				pType = ParamTypeString
-			}
-			pDesc := propSchema.Description
-			pParser := NewCommentParser().Parse(pDesc)
-			if pParser.HasTag(TagDescription) {
-				pDesc = pParser.GetString(TagDescription)
-			}
-			pItem := map[string]any{
-				"name":        propName,
-				"in":          ParamInFormData,
-				"type":        pType,
-				"description": pDesc,
+	// 处理响应
+	responses := newOrderedMap()
+	for _, code := range op.OrderedResponses() {
+		response := op.Responses[code]
+		responseMap := newOrderedMap()
+		responseMap.Set("description", response.Description)
+		if headers := convertResponseHeaders(&response, parser.GetResponseHeaderAnnotations(code), componentsSchemas); headers != nil {
+			responseMap.Set("headers", headers)
+		}
+		if response.Content != nil {
+			responseMap.Set("content", convertResponseContent(&response, successProduces(code, produces), componentsSchemas))
+		}
+		if len(response.Links) > 0 {
+			links := newOrderedMap()
+			for _, name := range response.OrderedLinks() {
+				link := response.Links[name]
+				links.Set(name, convertLinkToOpenAPI3(&link))
 			}
+			responseMap.Set("links", links)
+		}
+		copyExtensions(responseMap, response.Extensions)
+		responses.Set(code, responseMap)
+	}
+	// 追加 @response 标注声明的响应，文档中已显式定义的状态码优先
+	for _, annotation := range parser.GetResponseAnnotations() {
+		if responses.Has(annotation.Code) {
+			continue
+		}
+		responses.Set(annotation.Code, convertResponseAnnotation(annotation, produces, parser.GetResponseHeaderAnnotations(annotation.Code), componentsSchemas))
+	}
+	// 仅通过 @responseHeader 声明的状态码同样输出为响应
+	for _, annotation := range parser.GetResponseHeaderAnnotations("") {
+		if !responses.Has(annotation.Code) {
+			responses.Set(annotation.Code, convertResponseAnnotation(ResponseAnnotation{Code: annotation.Code}, nil, parser.GetResponseHeaderAnnotations(annotation.Code), componentsSchemas))
+		}
+	}
+	result.Set("responses", responses)
 
-			isFileField := pParser.HasTag(TagFile)
+	if parser.HasTag(TagOrder) {
+		result.Set(ExtOrder, int(parser.GetNumber(TagOrder)))
+	}
 
-			if reqSet[propName] || isFileField {
-				pItem["required"] = true
-			}
-			if isFileField {
-				pItem["type"] = ParamTypeFile
-			}
-			formDataParams = append(formDataParams, pItem)
-		}
-		if len(formDataParams) > 0 {
-			result["parameters"] = formDataParams
-		}
-	} else if len(op.Parameters) > 0 {
-		params := make([]map[string]any, len(op.Parameters))
-		for i, param := range op.Parameters {
-			pDesc := param.Description
-			pRequired := param.Required
-			pExample := param.Example
-
-			if pDesc != "" {
-				pParser := NewCommentParser().Parse(pDesc)
-				if parsedDesc := pParser.GetString(TagDescription); parsedDesc != "" {
-					pDesc = parsedDesc
-				}
-				if pParser.HasTag("required") {
-					pRequired = pParser.GetBool("required")
-				}
-				if pParser.HasTag("example") {
-					pExample = pParser.GetString("example")
-				}
-			}
+	if err := applyAnnotations(AnnotationOnOperation, parser, result); err != nil {
+		slog.Warn("自定义标注处理失败", "operationId", operationID, "err", err)
+	}
+	copyExtensions(result, op.Extensions)
+	return result
+}
 
-			paramMap := map[string]any{
-				"name":        param.Name,
-				"in":          param.In,
-				"description": pDesc,
-				"required":    pRequired,
-			}
-			if pExample != nil && pExample != "" {
-				paramMap["example"] = pExample
+// operationParameters 合并接口自身的参数、@header/@cookie/@query 标注声明的参数与继承的参数（路径级参数与全局参数），
+// 按此顺序排列，同一位置的同名参数以先出现者为准（header 名称不区分大小写）
+func operationParameters(op *Operation, parser *CommentParser, globals []Parameter) []Parameter {
+	annotated := parser.GetParameterAnnotations()
+	if len(annotated) == 0 && len(globals) == 0 {
+		return op.Parameters
+	}
+
+	key := func(param Parameter) string {
+		if param.In == ParamInHeader {
+			return param.In + ":" + strings.ToLower(param.Name)
+		}
+		return param.In + ":" + param.Name
+	}
+	seen := make(map[string]bool)
+	result := make([]Parameter, 0, len(op.Parameters)+len(annotated)+len(globals))
+	for _, param := range op.Parameters {
+		seen[key(param)] = true
+		result = append(result, param)
+	}
+	for _, group := range [][]Parameter{annotated, globals} {
+		for _, param := range group {
+			if param.In == "" {
+				param.In = ParamInHeader
 			}
-			if param.Schema != nil {
-				paramMap["schema"] = convertSchemaToOpenAPI3(param.Schema)
+			if seen[key(param)] {
+				continue
 			}
-			params[i] = paramMap
+			seen[key(param)] = true
+			result = append(result, param)
 		}
-		result["parameters"] = params
 	}
+	return result
+}
 
-	// 处理响应
-	responses := make(map[string]any)
-	for code, response := range op.Responses {
-		responseMap := make(map[string]any)
-		responseMap["description"] = response.Description
-		if response.Content != nil {
-			responseMap["content"] = convertContentToOpenAPI3(response.Content)
+// convertParameterToOpenAPI3 将 Parameter 转换为 OpenAPI 3.0 格式，描述中的标注覆盖参数自身的字段
+func convertParameterToOpenAPI3(param *Parameter, componentsSchemas map[string]Schema) *orderedMap {
+	pRequired := param.Required
+
+	// 描述仅包含标注时输出空描述，避免把标注原文展示给前端
+	pParser := parameterAnnotations(param)
+	pDesc := pParser.GetString(TagDescription)
+	if pParser.HasTag("required") {
+		pRequired = pParser.GetBool("required")
+	}
+
+	paramMap := newOrderedMap()
+	paramMap.Set("name", param.Name)
+	paramMap.Set("in", param.In)
+	if pDesc != "" {
+		paramMap.Set("description", pDesc)
+	}
+	if pRequired {
+		paramMap.Set("required", true)
+	}
+	if param.Deprecated || pParser.GetBool(TagDeprecated) {
+		paramMap.Set("deprecated", true)
+		appendDeprecation(paramMap, pParser.GetString(TagDeprecated))
+	}
+	if param.Example != nil && param.Example != "" {
+		paramMap.Set("example", param.Example)
+	}
+	if len(param.Examples) > 0 {
+		paramMap.Set("examples", param.Examples)
+	}
+	setExamples(paramMap, pParser, param.Schema, componentsSchemas)
+	if param.Schema != nil {
+		paramMap.Set("schema", convertSchemaToOpenAPI3(param.Schema, componentsSchemas))
+	}
+	if err := applyAnnotations(AnnotationOnParameter, pParser, paramMap); err != nil {
+		slog.Warn("自定义标注处理失败", "parameter", param.Name, "err", err)
+	}
+	copyExtensions(paramMap, param.Extensions)
+	return paramMap
+}
+
+// convertResponseHeaders 按源文件顺序输出响应头并追加 @responseHeader 标注声明的响应头，
+// 文档中已声明的同名响应头优先（名称不区分大小写），无响应头时返回 nil
+func convertResponseHeaders(response *Response, annotations []ResponseHeaderAnnotation, componentsSchemas map[string]Schema) *orderedMap {
+	if len(response.Headers) == 0 && len(annotations) == 0 {
+		return nil
+	}
+	headers := newOrderedMap()
+	declared := make(map[string]bool)
+	for _, name := range response.OrderedHeaders() {
+		header := response.Headers[name]
+		headers.Set(name, convertHeaderToOpenAPI3(&header, componentsSchemas))
+		declared[strings.ToLower(name)] = true
+	}
+	for _, annotation := range annotations {
+		if declared[strings.ToLower(annotation.Name)] {
+			continue
 		}
-		responses[code] = responseMap
+		declared[strings.ToLower(annotation.Name)] = true
+		headers.Set(annotation.Name, convertHeaderToOpenAPI3(&annotation.Header, componentsSchemas))
+	}
+	return headers
+}
+
+// convertHeaderToOpenAPI3 将响应头转换为 OpenAPI 3.0 格式，描述中的标注处理方式与参数相同
+func convertHeaderToOpenAPI3(header *Header, componentsSchemas map[string]Schema) *orderedMap {
+	parser := headerAnnotations(header)
+	result := newOrderedMap()
+	if description := parser.GetString(TagDescription); description != "" {
+		result.Set("description", description)
+	}
+	if header.Required || parser.GetBool("required") {
+		result.Set("required", true)
+	}
+	if header.Deprecated || parser.GetBool(TagDeprecated) {
+		result.Set("deprecated", true)
+		appendDeprecation(result, parser.GetString(TagDeprecated))
+	}
+	if header.Style != "" {
+		result.Set("style", header.Style)
+	}
+	if header.Explode {
+		result.Set("explode", true)
+	}
+	if header.Example != nil && header.Example != "" {
+		result.Set("example", header.Example)
+	}
+	if len(header.Examples) > 0 {
+		result.Set("examples", header.Examples)
 	}
-	result["responses"] = responses
+	setExamples(result, parser, header.Schema, componentsSchemas)
+	if header.Schema != nil {
+		result.Set("schema", convertSchemaToOpenAPI3(header.Schema, componentsSchemas))
+	}
+	if header.Content != nil {
+		result.Set("content", convertContentToOpenAPI3(header.Content, sortedKeys(header.Content), nil, componentsSchemas))
+	}
+	copyExtensions(result, header.Extensions)
+	return result
+}
 
+// convertLinkToOpenAPI3 将响应链接转换为 OpenAPI 3.0 格式
+func convertLinkToOpenAPI3(link *Link) *orderedMap {
+	result := newOrderedMap()
+	if link.OperationRef != "" {
+		result.Set("operationRef", link.OperationRef)
+	}
+	if link.OperationID != "" {
+		result.Set("operationId", link.OperationID)
+	}
+	if len(link.Parameters) > 0 {
+		result.Set("parameters", link.Parameters)
+	}
+	if link.RequestBody != nil {
+		result.Set("requestBody", link.RequestBody)
+	}
+	if link.Description != "" {
+		result.Set("description", link.Description)
+	}
+	if link.Server != nil {
+		result.Set("server", link.Server)
+	}
+	copyExtensions(result, link.Extensions)
 	return result
 }
 
-// convertContentToOpenAPI3 将 Content 转换为 OpenAPI 3.0 格式
-func convertContentToOpenAPI3(content map[string]MediaType) map[string]any {
-	result := make(map[string]any)
-	for contentType, mediaType := range content {
-		mediaTypeMap := make(map[string]any)
-		if mediaType.Schema != nil {
-			mediaTypeMap["schema"] = convertSchemaToOpenAPI3(mediaType.Schema)
+// convertResponseAnnotation 将 @response 标注转换为引用 components.schemas 的响应对象，
+// 未指定媒体类型时成功响应（2XX）使用 @produces 标注给出的类型，其余情况为 application/json
+func convertResponseAnnotation(annotation ResponseAnnotation, produces []string, headers []ResponseHeaderAnnotation, componentsSchemas map[string]Schema) *orderedMap {
+	description := annotation.Description
+	if description == "" {
+		description = DefaultResponseDescription(annotation.Code)
+	}
+	responseMap := newOrderedMap()
+	responseMap.Set("description", description)
+	if headersMap := convertResponseHeaders(&Response{}, headers, componentsSchemas); headersMap != nil {
+		responseMap.Set("headers", headersMap)
+	}
+	if annotation.Schema == "" {
+		return responseMap
+	}
+
+	ref := componentSchemaRef(annotation.Schema)
+	content := newOrderedMap()
+	for _, contentType := range responseAnnotationTypes(annotation, produces) {
+		schemaMap := newOrderedMap()
+		schemaMap.Set("$ref", ref)
+		mediaTypeMap := newOrderedMap()
+		mediaTypeMap.Set("schema", schemaMap)
+		content.Set(contentType, mediaTypeMap)
+	}
+	responseMap.Set("content", content)
+	return responseMap
+}
+
+// responseAnnotationTypes 返回 @response 标注响应的媒体类型
+func responseAnnotationTypes(annotation ResponseAnnotation, produces []string) []string {
+	if annotation.ContentType != "" {
+		return []string{annotation.ContentType}
+	}
+	if produces := successProduces(annotation.Code, produces); len(produces) > 0 {
+		return produces
+	}
+	return []string{MIMEApplicationJSON}
+}
+
+// successProduces 返回作用于该状态码的 @produces 媒体类型：仅成功响应（2XX）适用，
+// 错误响应保持原有的媒体类型，不随导出、下载类接口的 @produces 改变
+func successProduces(code string, produces []string) []string {
+	if strings.HasPrefix(code, "2") {
+		return produces
+	}
+	return nil
+}
+
+// convertResponseContent 按 @produces 标注调整响应的媒体类型并转换为 OpenAPI 3.0 格式，文件下载类型补充二进制 Schema
+func convertResponseContent(response *Response, produces []string, componentsSchemas map[string]Schema) *orderedMap {
+	content, contentTypes := responseContent(response, produces)
+	result := newOrderedMap()
+	for _, contentType := range contentTypes {
+		mediaType := content[contentType]
+		result.Set(contentType, convertMediaTypeToOpenAPI3(&mediaType, nil, componentsSchemas))
+	}
+	return result
+}
+
+// operationProduces 按响应顺序汇总接口的全部响应媒体类型，binary 表示其中存在文件下载响应
+func operationProduces(op *Operation, parser *CommentParser, produces []string) (contentTypes []string, binary bool) {
+	seen := make(map[string]bool)
+	add := func(contentType string, schema *Schema) {
+		binary = binary || isBinaryMediaType(contentType) || isBinarySchema(schema)
+		if !seen[contentType] {
+			seen[contentType] = true
+			contentTypes = append(contentTypes, contentType)
+		}
+	}
+	for _, code := range op.OrderedResponses() {
+		response := op.Responses[code]
+		content, order := preferredContent(response.Content, response.OrderedContentTypes(), successProduces(code, produces))
+		for _, contentType := range order {
+			add(contentType, content[contentType].Schema)
+		}
+	}
+	for _, annotation := range parser.GetResponseAnnotations() {
+		if _, declared := op.Responses[annotation.Code]; declared || annotation.Schema == "" {
+			continue
 		}
-		if mediaType.Example != nil {
-			mediaTypeMap["example"] = mediaType.Example
+		for _, contentType := range responseAnnotationTypes(annotation, produces) {
+			add(contentType, nil)
 		}
-		result[contentType] = mediaTypeMap
+	}
+	return contentTypes, binary
+}
+
+// DefaultResponseDescription 返回状态码的默认响应描述，用于未填写描述的 @response，
+// OpenAPI 要求 response.description 必填
+func DefaultResponseDescription(code string) string {
+	switch code {
+	case "default":
+		return "Default response"
+	case "1XX":
+		return "Informational"
+	case "2XX":
+		return "Success"
+	case "3XX":
+		return "Redirection"
+	case "4XX":
+		return "Client Error"
+	case "5XX":
+		return "Server Error"
+	}
+	if status, err := strconv.Atoi(code); err == nil && http.StatusText(status) != "" {
+		return http.StatusText(status)
+	}
+	return code
+}
+
+// convertContentToOpenAPI3 将 Content 按 contentTypes 给出的顺序转换为 OpenAPI 3.0 格式，
+// parser 不为空时其中的 @example 标注覆盖各媒体类型的示例
+func convertContentToOpenAPI3(content map[string]MediaType, contentTypes []string, parser *CommentParser, componentsSchemas map[string]Schema) *orderedMap {
+	result := newOrderedMap()
+	for _, contentType := range contentTypes {
+		mediaType := content[contentType]
+		result.Set(contentType, convertMediaTypeToOpenAPI3(&mediaType, parser, componentsSchemas))
 	}
 	return result
 }
 
-// convertSchemasToOpenAPI3 将 Schemas 转换为 OpenAPI 3.0 格式
-func convertSchemasToOpenAPI3(schemas map[string]Schema) map[string]any {
-	result := make(map[string]any)
-	for name, schema := range schemas {
-		result[name] = convertSchemaToOpenAPI3(&schema)
+// convertMediaTypeToOpenAPI3 将单个媒体类型转换为 OpenAPI 3.0 格式，parser 不为空时写入 @example 标注
+func convertMediaTypeToOpenAPI3(mediaType *MediaType, parser *CommentParser, componentsSchemas map[string]Schema) *orderedMap {
+	mediaTypeMap := newOrderedMap()
+	if mediaType.Schema != nil {
+		mediaTypeMap.Set("schema", convertSchemaToOpenAPI3(mediaType.Schema, componentsSchemas))
+	}
+	if mediaType.Example != nil {
+		mediaTypeMap.Set("example", mediaType.Example)
+	}
+	if len(mediaType.Examples) > 0 {
+		mediaTypeMap.Set("examples", mediaType.Examples)
+	}
+	if parser != nil {
+		setExamples(mediaTypeMap, parser, mediaType.Schema, componentsSchemas)
+	}
+	copyExtensions(mediaTypeMap, mediaType.Extensions)
+	return mediaTypeMap
+}
+
+// appendDeprecation 将 @deprecated 标注的说明追加到描述末尾
+func appendDeprecation(target *orderedMap, reason string) {
+	if reason == "" {
+		return
+	}
+	description, _ := target.Get("description")
+	text, _ := description.(string)
+	if text != "" {
+		text += "\n\n"
+	}
+	target.Set("description", text+"**Deprecated:** "+reason)
+}
+
+// setExamples 将 @example 标注写入参数或媒体类型对象，example 与 examples 互斥：
+// 仅有 @example 时覆盖原有的 example 并移除 examples；存在 @example[name] 时并入原有的 examples（同名以标注为准）并移除 example
+func setExamples(target *orderedMap, parser *CommentParser, schema *Schema, componentsSchemas map[string]Schema) {
+	named := parser.GetNamedExamples()
+	if len(named) == 0 {
+		if example, ok := parser.GetExample(); ok {
+			target.Delete("examples")
+			target.Set("example", exampleValue(example, schema, componentsSchemas))
+		}
+		return
+	}
+
+	examples := newOrderedMap()
+	if existing, ok := target.Get("examples"); ok {
+		if declared, ok := existing.(map[string]Example); ok {
+			for _, name := range sortedKeys(declared) {
+				examples.Set(name, declared[name])
+			}
+		}
+	}
+	for _, example := range named {
+		exampleMap := newOrderedMap()
+		exampleMap.Set("value", exampleValue(example, schema, componentsSchemas))
+		examples.Set(example.Name, exampleMap)
+	}
+	target.Delete("example")
+	target.Set("examples", examples)
+}
+
+// exampleValue 按 Schema 类型选择示例值：字符串类型保留原始文本，避免 "123" 被输出为数值。
+// $ref 按 components.schemas 解开后判断类型
+func exampleValue(example ExampleAnnotation, schema *Schema, componentsSchemas map[string]Schema) any {
+	if schema = resolveSchema(schema, componentsSchemas); schema != nil && schema.Type.Is(ParamTypeString) {
+		return example.Raw
+	}
+	return example.Value
+}
+
+// typedValue 按 Schema 类型解析 @default、@const 等标注的取值，规则与 exampleValue 相同
+func typedValue(raw string, schema *Schema, componentsSchemas map[string]Schema) any {
+	return exampleValue(ExampleAnnotation{Value: parseExampleValue(raw), Raw: strings.Trim(raw, "\"")}, schema, componentsSchemas)
+}
+
+// convertSchemasToOpenAPI3 将 components.schemas 按源文件顺序转换为 OpenAPI 3.0 格式
+func convertSchemasToOpenAPI3(components *Components) *orderedMap {
+	result := newOrderedMap()
+	for _, name := range components.OrderedSchemas() {
+		schema := components.Schemas[name]
+		result.Set(name, convertSchemaToOpenAPI3(&schema, components.Schemas))
+	}
+	return result
+}
+
+// convertSchemaList 依次转换 allOf/oneOf/anyOf 中的子 Schema
+func convertSchemaList(schemas []*Schema, componentsSchemas map[string]Schema) []*orderedMap {
+	result := make([]*orderedMap, 0, len(schemas))
+	for _, item := range schemas {
+		result = append(result, convertSchemaToOpenAPI3(item, componentsSchemas))
 	}
 	return result
 }
 
-// convertSchemaToOpenAPI3 将 Schema 转换为 OpenAPI 3.0 格式
-func convertSchemaToOpenAPI3(schema *Schema) map[string]any {
+// convertSchemaToOpenAPI3 将 Schema 转换为 OpenAPI 3.0 格式。
+// 优先级：注释标注 > Schema 自身字段，标注覆盖同名字段；@file 优先于 @format
+func convertSchemaToOpenAPI3(schema *Schema, componentsSchemas map[string]Schema) *orderedMap {
 	if schema == nil {
 		return nil
 	}
 
-	result := make(map[string]any)
+	result := newOrderedMap()
+
+	// 处理引用
+	if schema.Ref != "" {
+		result.Set("$ref", schema.Ref)
+	}
+
+	// 使用注释解析器处理描述
+	parser := schemaAnnotations(schema)
 
 	// 设置基本属性
-	if schema.Type != "" {
-		result["type"] = schema.Type
+	if len(schema.Type) > 0 {
+		result.Set("type", schema.Type.String())
 	}
 	if schema.Format != "" {
-		result["format"] = schema.Format
+		result.Set("format", schema.Format)
 	}
 	if schema.Title != "" {
-		result["title"] = schema.Title
-	}
-	if schema.Default != nil {
-		result["default"] = schema.Default
-	}
-
-	// 使用注释解析器处理描述
-	parser := NewCommentParser().Parse(schema.Description)
-
-	// OpenAPI 3.0 规范：若属性带有 @file 标注，强制转换为 type: "string", format: "binary"
-	if parser.HasTag(TagFile) {
-		result["type"] = ParamTypeString
-		result["format"] = ParamFormatBinary
+		result.Set("title", schema.Title)
 	}
 	// 从解析器中获取标签值
 	if parser.HasTag("description") {
-		result["description"] = parser.GetString("description")
+		result.Set("description", parser.GetString("description"))
 	}
-	if parser.HasTag("example") {
-		result["example"] = parser.GetString("example")
+	if schema.Default != nil {
+		result.Set("default", schema.Default)
 	}
-	if parser.HasTag("format") {
-		result["format"] = parser.GetString("format")
+	if schema.Example != nil {
+		result.Set("example", schema.Example)
 	}
-	if parser.HasTag("enum") {
-		result["enum"] = parser.GetArray("enum")
+
+	// 处理其他属性
+	if schema.MultipleOf != nil {
+		result.Set("multipleOf", *schema.MultipleOf)
 	}
-	if parser.HasTag("minLength") {
-		result["minLength"] = int64(parser.GetNumber("minLength"))
+	if schema.Maximum != nil {
+		result.Set("maximum", *schema.Maximum)
 	}
-	if parser.HasTag("maxLength") {
-		result["maxLength"] = int64(parser.GetNumber("maxLength"))
+	if schema.ExclusiveMaximum != nil && schema.ExclusiveMaximum.Bool {
+		result.Set("exclusiveMaximum", true)
 	}
-	if parser.HasTag("minimum") {
-		result["minimum"] = parser.GetNumber("minimum")
+	if schema.Minimum != nil {
+		result.Set("minimum", *schema.Minimum)
 	}
-	if parser.HasTag("maximum") {
-		result["maximum"] = parser.GetNumber("maximum")
+	if schema.ExclusiveMinimum != nil && schema.ExclusiveMinimum.Bool {
+		result.Set("exclusiveMinimum", true)
 	}
-	if parser.HasTag("pattern") {
-		result["pattern"] = strings.Trim(parser.GetString("pattern"), "\"")
-	}
-
-	// 处理其他属性
 	if schema.MaxLength != nil {
-		result["maxLength"] = schema.MaxLength
+		result.Set("maxLength", schema.MaxLength)
 	}
 	if schema.MinLength != nil {
-		result["minLength"] = schema.MinLength
+		result.Set("minLength", schema.MinLength)
 	}
 	if schema.Pattern != "" {
-		result["pattern"] = schema.Pattern
+		result.Set("pattern", schema.Pattern)
 	}
 	if schema.MaxItems != nil {
-		result["maxItems"] = schema.MaxItems
+		result.Set("maxItems", schema.MaxItems)
 	}
 	if schema.MinItems != nil {
-		result["minItems"] = schema.MinItems
+		result.Set("minItems", schema.MinItems)
+	}
+	if schema.UniqueItems {
+		result.Set("uniqueItems", true)
 	}
-	result["uniqueItems"] = schema.UniqueItems
 	if schema.MaxProperties != nil {
-		result["maxProperties"] = schema.MaxProperties
+		result.Set("maxProperties", schema.MaxProperties)
 	}
 	if schema.MinProperties != nil {
-		result["minProperties"] = schema.MinProperties
+		result.Set("minProperties", schema.MinProperties)
 	}
 	if len(schema.Required) > 0 {
-		result["required"] = schema.Required
+		result.Set("required", schema.Required)
 	}
 	if len(schema.Enum) > 0 {
-		result["enum"] = schema.Enum
+		result.Set("enum", schema.Enum)
 	}
 	if schema.Items != nil {
-		result["items"] = convertSchemaToOpenAPI3(schema.Items)
+		result.Set("items", convertSchemaToOpenAPI3(schema.Items, componentsSchemas))
 	}
 	if schema.AdditionalItems != nil {
-		result["additionalItems"] = convertSchemaToOpenAPI3(schema.AdditionalItems)
+		result.Set("additionalItems", convertSchemaToOpenAPI3(schema.AdditionalItems, componentsSchemas))
 	}
 	if len(schema.AllOf) > 0 {
-		allOf := make([]map[string]any, 0, len(schema.AllOf))
-		for _, item := range schema.AllOf {
-			allOf = append(allOf, convertSchemaToOpenAPI3(item))
-		}
-		result["allOf"] = allOf
+		result.Set("allOf", convertSchemaList(schema.AllOf, componentsSchemas))
 	}
 	if len(schema.OneOf) > 0 {
-		oneOf := make([]map[string]any, 0, len(schema.OneOf))
-		for _, item := range schema.OneOf {
-			oneOf = append(oneOf, convertSchemaToOpenAPI3(item))
-		}
-		result["oneOf"] = oneOf
+		result.Set("oneOf", convertSchemaList(schema.OneOf, componentsSchemas))
 	}
 	if len(schema.AnyOf) > 0 {
-		anyOf := make([]map[string]any, 0, len(schema.AnyOf))
-		for _, item := range schema.AnyOf {
-			anyOf = append(anyOf, convertSchemaToOpenAPI3(item))
-		}
-		result["anyOf"] = anyOf
+		result.Set("anyOf", convertSchemaList(schema.AnyOf, componentsSchemas))
 	}
 	if schema.Not != nil {
-		result["not"] = convertSchemaToOpenAPI3(schema.Not)
+		result.Set("not", convertSchemaToOpenAPI3(schema.Not, componentsSchemas))
+	}
+	if schema.Discriminator != nil {
+		result.Set("discriminator", convertDiscriminatorToOpenAPI3(schema.Discriminator))
 	}
 
-	// 处理属性定义
+	// 处理属性定义，保持源文件中的属性顺序
 	if schema.Properties != nil {
-		properties := make(map[string]any)
-		for name, prop := range schema.Properties {
-			properties[name] = convertSchemaToOpenAPI3(prop)
+		properties := newOrderedMap()
+		for _, name := range schema.OrderedProperties() {
+			properties.Set(name, convertSchemaToOpenAPI3(schema.Properties[name], componentsSchemas))
 		}
-		result["properties"] = properties
+		result.Set("properties", properties)
 	}
+	applyOneofGroups(result, schema)
 	if schema.AdditionalProperties != nil {
 		if schema.AdditionalProperties.IsBool {
-			result["additionalProperties"] = schema.AdditionalProperties.Allows
+			result.Set("additionalProperties", schema.AdditionalProperties.Allows)
 		} else if schema.AdditionalProperties.Schema != nil {
-			result["additionalProperties"] = convertSchemaToOpenAPI3(schema.AdditionalProperties.Schema)
+			result.Set("additionalProperties", convertSchemaToOpenAPI3(schema.AdditionalProperties.Schema, componentsSchemas))
 		}
 	}
 
-	// 处理引用
-	if schema.Ref != "" {
-		result["$ref"] = schema.Ref
+	// 设置其他属性
+	// 布尔属性仅在为 true 时输出
+	if schema.Nullable {
+		result.Set("nullable", true)
+	}
+	if schema.ReadOnly {
+		result.Set("readOnly", true)
+	}
+	if schema.WriteOnly {
+		result.Set("writeOnly", true)
+	}
+	if schema.Deprecated || parser.GetBool(TagDeprecated) {
+		result.Set("deprecated", true)
+		appendDeprecation(result, parser.GetString(TagDeprecated))
 	}
 
-	// 设置其他属性
-	result["nullable"] = schema.Nullable
-	result["readOnly"] = schema.ReadOnly
-	result["writeOnly"] = schema.WriteOnly
-	result["deprecated"] = schema.Deprecated
+	// 注释标注覆盖 Schema 自身字段
+	applySchemaAnnotations(result, parser, schema, componentsSchemas)
+
+	if err := applyAnnotations(AnnotationOnSchema, parser, result); err != nil {
+		slog.Warn("自定义标注处理失败", "err", err)
+	}
+	copyExtensions(result, schema.Extensions)
+	return wrapRefSiblings(result)
+}
+
+// wrapRefSiblings 将带有其他字段的 $ref 改写为 allOf: [{$ref}] 加同级字段。
+// OpenAPI 3.0 中 $ref 的同级字段会被忽略，部分校验工具还会报错，改写后描述、标注等内容得以保留
+func wrapRefSiblings(schema *orderedMap) *orderedMap {
+	ref, ok := schema.Get("$ref")
+	if !ok || schema.Len() == 1 {
+		return schema
+	}
+	refMap := newOrderedMap()
+	refMap.Set("$ref", ref)
+	allOf := []*orderedMap{refMap}
+	if existing, ok := schema.Get("allOf"); ok {
+		items, _ := existing.([]*orderedMap)
+		allOf = append(allOf, items...)
+	}
 
+	result := newOrderedMap()
+	result.Set("allOf", allOf)
+	for _, key := range schema.keys {
+		if key != "$ref" && key != "allOf" {
+			result.Set(key, schema.values[key])
+		}
+	}
 	return result
 }
+
+// applySchemaAnnotations 将 Schema 约束类标注写入输出结构，覆盖 Schema 自身的同名字段
+func applySchemaAnnotations(result *orderedMap, parser *CommentParser, schema *Schema, componentsSchemas map[string]Schema) {
+	if parser.HasTag("format") {
+		result.Set("format", parser.GetString("format"))
+	}
+	// OpenAPI 3.0 规范：若属性带有 @file 标注，强制转换为 type: "string", format: "binary"
+	if parser.HasTag(TagFile) {
+		result.Set("type", ParamTypeString)
+		result.Set("format", ParamFormatBinary)
+	}
+	if parser.HasTag("default") {
+		result.Set("default", typedValue(parser.GetString("default"), schema, componentsSchemas))
+	}
+	// OpenAPI 3.0 的 Schema 只支持单个 example，未声明默认示例时取第一个命名示例
+	if example, ok := parser.GetExample(); ok {
+		result.Set("example", exampleValue(example, schema, componentsSchemas))
+	} else if named := parser.GetNamedExamples(); len(named) > 0 {
+		result.Set("example", exampleValue(named[0], schema, componentsSchemas))
+	}
+
+	// 数值约束，整数型关键字输出为整数
+	for _, tag := range []string{"multipleOf", "maximum", "minimum"} {
+		if parser.HasTag(tag) {
+			result.Set(tag, parser.GetNumber(tag))
+		}
+	}
+	for _, tag := range []string{"maxLength", "minLength", "maxItems", "minItems"} {
+		if parser.HasTag(tag) {
+			result.Set(tag, int64(parser.GetNumber(tag)))
+		}
+	}
+	// 排他边界：布尔值沿用 3.0 写法，数值按 3.1 写法转换为边界 + 布尔标记
+	for _, pair := range [][2]string{{"exclusiveMaximum", "maximum"}, {"exclusiveMinimum", "minimum"}} {
+		bound, keyword := pair[0], pair[1]
+		if _, isNumber := parser.numberTags[bound]; isNumber {
+			result.Set(keyword, parser.GetNumber(bound))
+			result.Set(bound, true)
+		} else if parser.HasTag(bound) {
+			result.Set(bound, parser.GetBool(bound))
+		}
+	}
+	if parser.HasTag("pattern") {
+		result.Set("pattern", strings.Trim(parser.GetString("pattern"), "\""))
+	}
+
+	applyEnumAnnotation(result, parser, schema)
+	// OpenAPI 3.0 没有 const，与 3.1 降级规则一致输出为单值 enum
+	if parser.HasTag("const") {
+		result.Set("enum", []any{typedValue(parser.GetString("const"), schema, componentsSchemas)})
+	}
+
+	for _, tag := range []string{"uniqueItems", "nullable", "readOnly", "writeOnly"} {
+		if _, exists := parser.boolTags[tag]; !exists {
+			continue
+		}
+		if parser.GetBool(tag) {
+			result.Set(tag, true)
+		} else {
+			result.Delete(tag)
+		}
+	}
+
+	applyPolymorphismAnnotations(result, parser)
+}
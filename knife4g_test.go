@@ -0,0 +1,158 @@
+package knife4g
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+// serve 以 cfg 创建服务并请求 target，返回响应记录
+func serve(t *testing.T, cfg *Config, target string) *httptest.ResponseRecorder {
+	t.Helper()
+	server, err := NewKnife4jServer(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", target, nil)
+	switch r.URL.Path {
+	case "/v2/api-docs":
+		server.handleSwagger2Docs(w, r)
+	default:
+		server.handleOpenAPIDocs(w, r, negotiateDocFormat(r, r.URL.Path))
+	}
+	if w.Code != 200 {
+		t.Fatalf("GET %s: status %d: %s", target, w.Code, w.Body)
+	}
+	return w
+}
+
+// serveJSON 请求 target 并将 JSON 响应解码为通用结构
+func serveJSON(t *testing.T, cfg *Config, target string) map[string]any {
+	t.Helper()
+	var doc map[string]any
+	if err := json.Unmarshal(serve(t, cfg, target).Body.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	return doc
+}
+
+// lookup 按路径逐层取出通用结构中的值，不存在时返回 nil
+func lookup(value any, keys ...string) any {
+	for _, key := range keys {
+		object, ok := value.(map[string]any)
+		if !ok {
+			return nil
+		}
+		value = object[key]
+	}
+	return value
+}
+
+// keysOf 返回通用结构中对象的全部键，值不是对象时返回 nil
+func keysOf(value any) map[string]bool {
+	object, ok := value.(map[string]any)
+	if !ok {
+		return nil
+	}
+	keys := make(map[string]bool, len(object))
+	for key := range object {
+		keys[key] = true
+	}
+	return keys
+}
+
+// orderedLookup 按路径逐层取出转换结果中的对象，不存在时返回 nil
+func orderedLookup(m *orderedMap, keys ...string) *orderedMap {
+	for _, key := range keys {
+		if m == nil {
+			return nil
+		}
+		value, _ := m.Get(key)
+		m, _ = value.(*orderedMap)
+	}
+	return m
+}
+
+func TestWrapRefSiblings(t *testing.T) {
+	user := refSchema("User")
+	tests := []struct {
+		name   string
+		schema *Schema
+		want   string
+	}{
+		{"plain ref", refSchema("User"), `{"$ref":"#/components/schemas/User"}`},
+		{
+			"ref with siblings",
+			&Schema{Ref: user.Ref, Description: "负责人", Nullable: true},
+			`{"allOf":[{"$ref":"#/components/schemas/User"}],"description":"负责人","nullable":true}`,
+		},
+		{
+			"ref with allOf",
+			&Schema{Ref: user.Ref, AllOf: []*Schema{refSchema("Audit")}, Description: "负责人"},
+			`{"allOf":[{"$ref":"#/components/schemas/User"},{"$ref":"#/components/schemas/Audit"}],"description":"负责人"}`,
+		},
+		{
+			"ref with annotations",
+			&Schema{Ref: user.Ref, Description: "负责人\n@readOnly"},
+			`{"allOf":[{"$ref":"#/components/schemas/User"}],"description":"负责人","readOnly":true}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(convertSchemaToOpenAPI3(tt.schema, nil))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != tt.want {
+				t.Errorf("got  %s\nwant %s", data, tt.want)
+			}
+		})
+	}
+}
+
+// TestDocumentOutputDeterministic 同一文档多次请求以及从其编码结果重新解码的副本输出的字节均须一致
+func TestDocumentOutputDeterministic(t *testing.T) {
+	data, err := os.ReadFile("testdata/roundtrip.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var parsed OpenAPI3
+	if err := yaml.Unmarshal(data, &parsed); err != nil {
+		t.Fatal(err)
+	}
+	docs := map[string]*OpenAPI3{
+		"parsed":   &parsed,
+		"swagger2": petstoreSwagger2(t),
+		"defs":     defsDoc(),
+		"built":    schemaDoc(objectSchema(map[string]*Schema{"a": stringSchema(), "b": refSchema("B"), "c": stringSchema("x", "y")}), map[string]Schema{"B": *objectSchema(map[string]*Schema{"z": stringSchema(), "y": stringSchema()})}),
+	}
+	targets := []string{"/v3/api-docs", "/v3/api-docs.yaml", "/v3/api-docs?raw=true", "/v2/api-docs"}
+
+	for name, doc := range docs {
+		// 先经 JSON 编码再解码得到与原文档等价的新副本
+		encoded, err := json.Marshal(doc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var copied OpenAPI3
+		if err := json.Unmarshal(encoded, &copied); err != nil {
+			t.Fatal(err)
+		}
+
+		for _, target := range targets {
+			want := serve(t, &Config{OpenAPI: doc, EnableSwagger2: true}, target).Body.String()
+			for i := 0; i < 10; i++ {
+				if got := serve(t, &Config{OpenAPI: doc, EnableSwagger2: true}, target).Body.String(); got != want {
+					t.Fatalf("%s %s: request %d differs:\n%s\nwant\n%s", name, target, i, got, want)
+				}
+			}
+			if got := serve(t, &Config{OpenAPI: &copied, EnableSwagger2: true}, target).Body.String(); got != want {
+				t.Errorf("%s %s: decoded copy differs:\n%s\nwant\n%s", name, target, got, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package knife4g
+
+import "strings"
+
+// binaryMediaTypes 除 image/*、audio/*、video/* 外按文件下载处理的响应媒体类型
+var binaryMediaTypes = map[string]bool{
+	MIMEApplicationOctetStream: true,
+	"application/pdf":          true,
+	"application/zip":          true,
+	"application/gzip":         true,
+	"application/vnd.ms-excel": true,
+	"text/csv":                 true,
+}
+
+// isBinaryMediaType 判断响应媒体类型是否为文件下载，参数部分（如 "; charset=utf-8"）不参与判断
+func isBinaryMediaType(contentType string) bool {
+	contentType, _, _ = strings.Cut(contentType, ";")
+	contentType = strings.ToLower(strings.TrimSpace(contentType))
+	return binaryMediaTypes[contentType] ||
+		strings.HasPrefix(contentType, "application/vnd.openxmlformats-officedocument.") ||
+		strings.HasPrefix(contentType, "image/") ||
+		strings.HasPrefix(contentType, "audio/") ||
+		strings.HasPrefix(contentType, "video/")
+}
+
+// preferredContent 按 preferred 给出的媒体类型调整 content 的顺序，preferred 中的第一项为默认类型，
+// 其余源文件中的媒体类型保持原有顺序排在之后。content 中缺少的类型沿用首个带 Schema 的媒体类型；
+// 由此新增的表单类型替换其来源，避免同一请求体同时以 JSON 与表单展示
+func preferredContent(content map[string]MediaType, contentTypes, preferred []string) (map[string]MediaType, []string) {
+	if len(preferred) == 0 || len(content) == 0 {
+		return content, contentTypes
+	}
+	source := contentTypes[0]
+	for _, contentType := range contentTypes {
+		if content[contentType].Schema != nil {
+			source = contentType
+			break
+		}
+	}
+
+	result := make(map[string]MediaType, len(content)+len(preferred))
+	order := make([]string, 0, len(content)+len(preferred))
+	replaced := false
+	for _, contentType := range preferred {
+		if _, exists := result[contentType]; exists {
+			continue
+		}
+		media, exists := content[contentType]
+		if !exists {
+			media = content[source]
+			replaced = replaced || isFormContentType(contentType)
+		}
+		result[contentType] = media
+		order = append(order, contentType)
+	}
+	for _, contentType := range contentTypes {
+		if _, exists := result[contentType]; exists || (replaced && contentType == source) {
+			continue
+		}
+		result[contentType] = content[contentType]
+		order = append(order, contentType)
+	}
+	return result, order
+}
+
+// requestContentTypes 返回 @consumes 标注给出的请求媒体类型；仅有 @file 标注时为 multipart/form-data
+func requestContentTypes(parser *CommentParser) []string {
+	if consumes := parser.GetArray(TagConsumes); len(consumes) > 0 {
+		return consumes
+	}
+	if parser.HasTag(TagFile) {
+		return []string{MIMEMultipartFormData}
+	}
+	return nil
+}
+
+// responseContent 按 @produces 给出的媒体类型调整响应的 content 及其顺序，文件下载类型补充二进制 Schema；
+// /v3/api-docs 与 Swagger 2.0 导出共用，保证两者的默认媒体类型一致
+func responseContent(response *Response, produces []string) (map[string]MediaType, []string) {
+	content, contentTypes := preferredContent(response.Content, response.OrderedContentTypes(), produces)
+	result := make(map[string]MediaType, len(content))
+	for _, contentType := range contentTypes {
+		mediaType := content[contentType]
+		if _, declared := response.Content[contentType]; !declared && isBinaryMediaType(contentType) {
+			// @produces 新增的文件类型不沿用其他媒体类型的 Schema
+			mediaType = MediaType{}
+		}
+		markBinaryResponse(contentType, &mediaType)
+		result[contentType] = mediaType
+	}
+	return result, contentTypes
+}
+
+// markBinaryResponse 为文件下载类型的响应补充 type: string, format: binary 的 Schema，
+// Knife4j 调试面板据此将响应作为文件下载
+func markBinaryResponse(contentType string, mediaType *MediaType) {
+	if mediaType.Schema == nil && isBinaryMediaType(contentType) {
+		mediaType.Schema = &Schema{Type: SchemaType{ParamTypeString}, Format: ParamFormatBinary}
+	}
+}
@@ -0,0 +1,101 @@
+package knife4g
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestProducesAppliesToSuccessResponses(t *testing.T) {
+	errorContent := map[string]MediaType{MIMEApplicationJSON: {Schema: refSchema("Err")}}
+	doc := &OpenAPI3{
+		OpenAPI: "3.0.3",
+		Info:    Info{Title: "t", Version: "1"},
+		Paths: map[string]PathItem{
+			"/export": {Get: &Operation{
+				Description: "@produces: text/csv\n@response: 404: Err | not found",
+				Responses: map[string]Response{
+					"200": {Description: "OK", Content: map[string]MediaType{MIMEApplicationJSON: {Schema: refSchema("Report")}}},
+					"500": {Description: "Server Error", Content: errorContent},
+				},
+			}},
+		},
+		Components: Components{Schemas: map[string]Schema{
+			"Err":    *objectSchema(map[string]*Schema{"message": {Type: SchemaType{"string"}}}),
+			"Report": *objectSchema(map[string]*Schema{"rows": {Type: SchemaType{"integer"}}}),
+		}},
+	}
+	responses := lookup(serveJSON(t, &Config{OpenAPI: doc}, "/v3/api-docs"), "paths", "/export", "get", "responses")
+
+	tests := []struct {
+		code string
+		want map[string]bool
+	}{
+		{"200", map[string]bool{"text/csv": true, MIMEApplicationJSON: true}},
+		{"404", map[string]bool{MIMEApplicationJSON: true}},
+		{"500", map[string]bool{MIMEApplicationJSON: true}},
+	}
+	for _, tt := range tests {
+		if got := keysOf(lookup(responses, tt.code, "content")); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("response %s content types = %v, want %v", tt.code, got, tt.want)
+		}
+	}
+	if got := lookup(responses, "200", "content", "text/csv", "schema", "format"); got != ParamFormatBinary {
+		t.Errorf("text/csv schema format = %v, want binary", got)
+	}
+	for _, code := range []string{"404", "500"} {
+		if got := lookup(responses, code, "content", MIMEApplicationJSON, "schema", "$ref"); got != "#/components/schemas/Err" {
+			t.Errorf("response %s schema = %v, want Err", code, got)
+		}
+	}
+}
+
+func TestSwagger2MediaTypeOrderMatchesOpenAPI3(t *testing.T) {
+	schema := objectSchema(map[string]*Schema{"id": {Type: SchemaType{"string"}}})
+	content := func(types ...string) map[string]MediaType {
+		result := make(map[string]MediaType, len(types))
+		for _, contentType := range types {
+			result[contentType] = MediaType{Schema: schema}
+		}
+		return result
+	}
+	body := &RequestBody{Content: content(MIMEApplicationJSON, "application/xml")}
+	body.contentOrder = []string{"application/xml", MIMEApplicationJSON}
+	response := Response{Description: "OK", Content: content(MIMEApplicationJSON, "text/plain")}
+	response.contentOrder = []string{"text/plain", MIMEApplicationJSON}
+	annotated := Response{Description: "OK", Content: content(MIMEApplicationJSON)}
+
+	doc := &OpenAPI3{
+		OpenAPI: "3.0.3",
+		Info:    Info{Title: "t", Version: "1"},
+		Paths: map[string]PathItem{
+			"/source":    {Post: &Operation{RequestBody: body, Responses: map[string]Response{"200": response}}},
+			"/annotated": {Get: &Operation{Description: "@produces: application/xml", Responses: map[string]Response{"200": annotated}}},
+		},
+	}
+	cfg := &Config{OpenAPI: doc, EnableSwagger2: true}
+	server, err := NewKnife4jServer(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v3 := convertToOpenAPI3(server.document, cfg, false)
+	v2 := serveJSON(t, cfg, "/v2/api-docs")
+
+	tests := []struct {
+		name      string
+		v3, v2    []string
+		wantFirst string
+	}{
+		{"request body", []string{"paths", "/source", "post", "requestBody", "content"}, []string{"paths", "/source", "post", "consumes"}, "application/xml"},
+		{"response", []string{"paths", "/source", "post", "responses", "200", "content"}, []string{"paths", "/source", "post", "produces"}, "text/plain"},
+		{"@produces", []string{"paths", "/annotated", "get", "responses", "200", "content"}, []string{"paths", "/annotated", "get", "produces"}, "application/xml"},
+	}
+	for _, tt := range tests {
+		if content := orderedLookup(v3, tt.v3...); content == nil || content.keys[0] != tt.wantFirst {
+			t.Errorf("%s: /v3/api-docs content = %v, want %s first", tt.name, content, tt.wantFirst)
+		}
+		types, _ := lookup(v2, tt.v2...).([]any)
+		if len(types) == 0 || types[0] != tt.wantFirst {
+			t.Errorf("%s: /v2/api-docs media types = %v, want %s first", tt.name, types, tt.wantFirst)
+		}
+	}
+}
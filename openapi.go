@@ -3,35 +3,50 @@ package knife4g
 import (
 	"bytes"
 	"encoding/json"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 )
 
-// OpenAPI3 表示 OpenAPI 3.0 规范的结构
+// OpenAPI3 表示 OpenAPI 3.0/3.1 规范的结构
 type OpenAPI3 struct {
-	OpenAPI    string              `json:"openapi" yaml:"openapi"`
-	Info       Info                `json:"info" yaml:"info"`
-	Paths      map[string]PathItem `json:"paths" yaml:"paths"`
-	Components Components          `json:"components" yaml:"components"`
-	Tags       []Tag               `json:"tags" yaml:"tags"`
-	Servers    []Server            `json:"servers" yaml:"servers"`
+	OpenAPI           string                 `json:"openapi" yaml:"openapi"`
+	Info              Info                   `json:"info" yaml:"info"`
+	JSONSchemaDialect string                 `json:"jsonSchemaDialect,omitempty" yaml:"jsonSchemaDialect,omitempty"` // 3.1
+	Paths             map[string]PathItem    `json:"paths" yaml:"paths"`
+	Webhooks          map[string]PathItem    `json:"webhooks,omitempty" yaml:"webhooks,omitempty"` // 3.1
+	Components        Components             `json:"components" yaml:"components"`
+	Tags              []Tag                  `json:"tags" yaml:"tags"`
+	Servers           []Server               `json:"servers" yaml:"servers"`
+	Security          []SecurityRequirement  `json:"security,omitempty" yaml:"security,omitempty"`
+	ExternalDocs      *ExternalDocumentation `json:"externalDocs,omitempty" yaml:"externalDocs,omitempty"`
+	Extensions        Extensions             `json:"-" yaml:"-"`
+
+	pathOrder []string // paths 在源文件中的出现顺序
+}
+
+// IsOpenAPI31 判断文档是否声明为 OpenAPI 3.1.x
+func (o *OpenAPI3) IsOpenAPI31() bool {
+	return strings.HasPrefix(o.OpenAPI, "3.1")
 }
 
 // Info 包含 API 的基本信息
 type Info struct {
-	Title          string   `json:"title" yaml:"title"`
-	Description    string   `json:"description" yaml:"description"`
-	Version        string   `json:"version" yaml:"version"`
-	Contact        *Contact `json:"contact,omitempty" yaml:"contact,omitempty"`
-	TermsOfService string   `json:"termsOfService,omitempty" yaml:"termsOfService,omitempty"`
-	Name           string   `json:"name,omitempty" yaml:"name,omitempty"`
+	Title          string     `json:"title" yaml:"title"`
+	Description    string     `json:"description" yaml:"description"`
+	Version        string     `json:"version" yaml:"version"`
+	Contact        *Contact   `json:"contact,omitempty" yaml:"contact,omitempty"`
+	TermsOfService string     `json:"termsOfService,omitempty" yaml:"termsOfService,omitempty"`
+	Name           string     `json:"name,omitempty" yaml:"name,omitempty"`
+	Extensions     Extensions `json:"-" yaml:"-"`
 }
 
 // Contact 包含联系信息
 type Contact struct {
-	Name  string `json:"name,omitempty" yaml:"name,omitempty"`
-	URL   string `json:"url,omitempty" yaml:"url,omitempty"`
-	Email string `json:"email,omitempty" yaml:"email,omitempty"`
+	Name       string     `json:"name,omitempty" yaml:"name,omitempty"`
+	URL        string     `json:"url,omitempty" yaml:"url,omitempty"`
+	Email      string     `json:"email,omitempty" yaml:"email,omitempty"`
+	Extensions Extensions `json:"-" yaml:"-"`
 }
 
 // Server 表示服务器信息
@@ -39,13 +54,15 @@ type Server struct {
 	URL         string                    `json:"url" yaml:"url"`
 	Description string                    `json:"description,omitempty" yaml:"description,omitempty"`
 	Variables   map[string]ServerVariable `json:"variables,omitempty" yaml:"variables,omitempty"`
+	Extensions  Extensions                `json:"-" yaml:"-"`
 }
 
 // ServerVariable 表示服务器变量
 type ServerVariable struct {
-	Default     string   `json:"default" yaml:"default"`
-	Description string   `json:"description,omitempty" yaml:"description,omitempty"`
-	Enum        []string `json:"enum,omitempty" yaml:"enum,omitempty"`
+	Default     string     `json:"default" yaml:"default"`
+	Description string     `json:"description,omitempty" yaml:"description,omitempty"`
+	Enum        []string   `json:"enum,omitempty" yaml:"enum,omitempty"`
+	Extensions  Extensions `json:"-" yaml:"-"`
 }
 
 // PathItem 表示路径项
@@ -59,6 +76,7 @@ type PathItem struct {
 	Delete      *Operation  `json:"delete,omitempty" yaml:"delete,omitempty"`
 	Patch       *Operation  `json:"patch,omitempty" yaml:"patch,omitempty"`
 	Parameters  []Parameter `json:"parameters,omitempty" yaml:"parameters,omitempty"`
+	Extensions  Extensions  `json:"-" yaml:"-"`
 }
 
 // Operation 表示 API 操作
@@ -74,6 +92,10 @@ type Operation struct {
 	Deprecated  bool                  `json:"deprecated,omitempty" yaml:"deprecated,omitempty"`
 	Security    []SecurityRequirement `json:"security,omitempty" yaml:"security,omitempty"`
 	Servers     []Server              `json:"servers,omitempty" yaml:"servers,omitempty"`
+	Extensions  Extensions            `json:"-" yaml:"-"`
+	Doc         *OperationDescription `json:"-" yaml:"-"` // 由 AnnotateDocument 填充的注释解析结果
+
+	responseOrder []string // responses 在源文件中的出现顺序
 }
 
 // Parameter 表示参数
@@ -91,6 +113,8 @@ type Parameter struct {
 	Example         interface{}          `json:"example,omitempty" yaml:"example,omitempty"`
 	Examples        map[string]Example   `json:"examples,omitempty" yaml:"examples,omitempty"`
 	Content         map[string]MediaType `json:"content,omitempty" yaml:"content,omitempty"`
+	Extensions      Extensions           `json:"-" yaml:"-"`
+	Doc             *FieldDoc            `json:"-" yaml:"-"` // 由 AnnotateDocument 填充的注释解析结果
 }
 
 // RequestBody 表示请求体
@@ -98,6 +122,9 @@ type RequestBody struct {
 	Description string               `json:"description,omitempty" yaml:"description,omitempty"`
 	Content     map[string]MediaType `json:"content" yaml:"content"`
 	Required    bool                 `json:"required,omitempty" yaml:"required,omitempty"`
+	Extensions  Extensions           `json:"-" yaml:"-"`
+
+	contentOrder []string // content 在源文件中的出现顺序
 }
 
 // Response 表示响应
@@ -106,6 +133,11 @@ type Response struct {
 	Headers     map[string]Header    `json:"headers,omitempty" yaml:"headers,omitempty"`
 	Content     map[string]MediaType `json:"content,omitempty" yaml:"content,omitempty"`
 	Links       map[string]Link      `json:"links,omitempty" yaml:"links,omitempty"`
+	Extensions  Extensions           `json:"-" yaml:"-"`
+
+	contentOrder []string // content 在源文件中的出现顺序
+	headerOrder  []string // headers 在源文件中的出现顺序
+	linkOrder    []string // links 在源文件中的出现顺序
 }
 
 // Components 表示组件
@@ -119,11 +151,14 @@ type Components struct {
 	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes,omitempty" yaml:"securitySchemes,omitempty"`
 	Links           map[string]Link           `json:"links,omitempty" yaml:"links,omitempty"`
 	Callbacks       map[string]Callback       `json:"callbacks,omitempty" yaml:"callbacks,omitempty"`
+	Extensions      Extensions                `json:"-" yaml:"-"`
+
+	schemaOrder []string // schemas 在源文件中的出现顺序
 }
 
-// Schema 表示模式
+// Schema 表示模式，同时覆盖 OpenAPI 3.0 与 3.1（JSON Schema 2020-12）的关键字
 type Schema struct {
-	Type                 string                 `json:"type,omitempty" yaml:"type,omitempty"`
+	Type                 SchemaType             `json:"type,omitempty" yaml:"type,omitempty"`
 	Format               string                 `json:"format,omitempty" yaml:"format,omitempty"`
 	Title                string                 `json:"title,omitempty" yaml:"title,omitempty"`
 	Description          string                 `json:"description,omitempty" yaml:"description,omitempty"`
@@ -131,9 +166,9 @@ type Schema struct {
 	Default              interface{}            `json:"default,omitempty" yaml:"default,omitempty"`
 	MultipleOf           *float64               `json:"multipleOf,omitempty" yaml:"multipleOf,omitempty"`
 	Maximum              *float64               `json:"maximum,omitempty" yaml:"maximum,omitempty"`
-	ExclusiveMaximum     bool                   `json:"exclusiveMaximum,omitempty" yaml:"exclusiveMaximum,omitempty"`
+	ExclusiveMaximum     *BoolOrNumber          `json:"exclusiveMaximum,omitempty" yaml:"exclusiveMaximum,omitempty"`
 	Minimum              *float64               `json:"minimum,omitempty" yaml:"minimum,omitempty"`
-	ExclusiveMinimum     bool                   `json:"exclusiveMinimum,omitempty" yaml:"exclusiveMinimum,omitempty"`
+	ExclusiveMinimum     *BoolOrNumber          `json:"exclusiveMinimum,omitempty" yaml:"exclusiveMinimum,omitempty"`
 	MaxLength            *int                   `json:"maxLength,omitempty" yaml:"maxLength,omitempty"`
 	MinLength            *int                   `json:"minLength,omitempty" yaml:"minLength,omitempty"`
 	Pattern              string                 `json:"pattern,omitempty" yaml:"pattern,omitempty"`
@@ -144,6 +179,7 @@ type Schema struct {
 	MinProperties        *int                   `json:"minProperties,omitempty" yaml:"minProperties,omitempty"`
 	Required             []string               `json:"required,omitempty" yaml:"required,omitempty"`
 	Enum                 []interface{}          `json:"enum,omitempty" yaml:"enum,omitempty"`
+	Const                interface{}            `json:"const,omitempty" yaml:"const,omitempty"` // 3.1
 	Properties           map[string]*Schema     `json:"properties,omitempty" yaml:"properties,omitempty"`
 	AllOf                []*Schema              `json:"allOf,omitempty" yaml:"allOf,omitempty"`
 	OneOf                []*Schema              `json:"oneOf,omitempty" yaml:"oneOf,omitempty"`
@@ -152,6 +188,7 @@ type Schema struct {
 	Items                *Schema                `json:"items,omitempty" yaml:"items,omitempty"`
 	AdditionalItems      *Schema                `json:"additionalItems,omitempty" yaml:"additionalItems,omitempty"`
 	AdditionalProperties *SchemaOrBool          `json:"additionalProperties,omitempty" yaml:"additionalProperties,omitempty"`
+	Defs                 map[string]*Schema     `json:"$defs,omitempty" yaml:"$defs,omitempty"` // 3.1
 	Ref                  string                 `json:"$ref,omitempty" yaml:"$ref,omitempty"`
 	Nullable             bool                   `json:"nullable,omitempty" yaml:"nullable,omitempty"`
 	Discriminator        *Discriminator         `json:"discriminator,omitempty" yaml:"discriminator,omitempty"`
@@ -160,7 +197,129 @@ type Schema struct {
 	XML                  *XML                   `json:"xml,omitempty" yaml:"xml,omitempty"`
 	ExternalDocs         *ExternalDocumentation `json:"externalDocs,omitempty" yaml:"externalDocs,omitempty"`
 	Example              interface{}            `json:"example,omitempty" yaml:"example,omitempty"`
+	Examples             []interface{}          `json:"examples,omitempty" yaml:"examples,omitempty"`                 // 3.1
+	ContentMediaType     string                 `json:"contentMediaType,omitempty" yaml:"contentMediaType,omitempty"` // 3.1
+	ContentEncoding      string                 `json:"contentEncoding,omitempty" yaml:"contentEncoding,omitempty"`   // 3.1
 	Deprecated           bool                   `json:"deprecated,omitempty" yaml:"deprecated,omitempty"`
+	Extensions           Extensions             `json:"-" yaml:"-"`
+
+	propertyOrder []string // properties 在源文件中的出现顺序
+}
+
+// SchemaType 表示 Schema 的 type 关键字：OpenAPI 3.0 中为单个字符串，3.1 中可为字符串数组（如 ["string", "null"]）
+type SchemaType []string
+
+// String 返回单一类型名称，多类型时以逗号连接
+func (t SchemaType) String() string {
+	return strings.Join(t, ",")
+}
+
+// Is 判断类型列表中是否包含指定类型
+func (t SchemaType) Is(name string) bool {
+	for _, v := range t {
+		if v == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (t SchemaType) MarshalJSON() ([]byte, error) {
+	if len(t) == 1 {
+		return json.Marshal(t[0])
+	}
+	return json.Marshal([]string(t))
+}
+
+func (t SchemaType) MarshalYAML() (interface{}, error) {
+	if len(t) == 1 {
+		return t[0], nil
+	}
+	return []string(t), nil
+}
+
+func (t *SchemaType) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		var name string
+		if err := value.Decode(&name); err != nil {
+			return err
+		}
+		*t = SchemaType{name}
+		return nil
+	}
+	var names []string
+	if err := value.Decode(&names); err != nil {
+		return err
+	}
+	*t = names
+	return nil
+}
+
+func (t *SchemaType) UnmarshalJSON(data []byte) error {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 || bytes.Equal(trimmed, []byte("null")) {
+		return nil
+	}
+	var name string
+	if err := json.Unmarshal(trimmed, &name); err == nil {
+		*t = SchemaType{name}
+		return nil
+	}
+	var names []string
+	if err := json.Unmarshal(trimmed, &names); err != nil {
+		return err
+	}
+	*t = names
+	return nil
+}
+
+// BoolOrNumber handles exclusiveMaximum/exclusiveMinimum being a boolean (3.0) or a number (3.1).
+type BoolOrNumber struct {
+	Bool     bool
+	Number   float64
+	IsNumber bool
+}
+
+func (b BoolOrNumber) MarshalJSON() ([]byte, error) {
+	if b.IsNumber {
+		return json.Marshal(b.Number)
+	}
+	return json.Marshal(b.Bool)
+}
+
+func (b BoolOrNumber) MarshalYAML() (interface{}, error) {
+	if b.IsNumber {
+		return b.Number, nil
+	}
+	return b.Bool, nil
+}
+
+func (b *BoolOrNumber) UnmarshalYAML(value *yaml.Node) error {
+	var v bool
+	if err := value.Decode(&v); err == nil {
+		*b = BoolOrNumber{Bool: v}
+		return nil
+	}
+	var n float64
+	if err := value.Decode(&n); err != nil {
+		return err
+	}
+	*b = BoolOrNumber{Number: n, IsNumber: true}
+	return nil
+}
+
+func (b *BoolOrNumber) UnmarshalJSON(data []byte) error {
+	var v bool
+	if err := json.Unmarshal(data, &v); err == nil {
+		*b = BoolOrNumber{Bool: v}
+		return nil
+	}
+	var n float64
+	if err := json.Unmarshal(data, &n); err != nil {
+		return err
+	}
+	*b = BoolOrNumber{Number: n, IsNumber: true}
+	return nil
 }
 
 // SchemaOrBool handles additionalProperties being either a boolean or a schema.
@@ -170,6 +329,20 @@ type SchemaOrBool struct {
 	IsBool bool
 }
 
+func (s SchemaOrBool) MarshalJSON() ([]byte, error) {
+	if s.IsBool {
+		return json.Marshal(s.Allows)
+	}
+	return json.Marshal(s.Schema)
+}
+
+func (s SchemaOrBool) MarshalYAML() (interface{}, error) {
+	if s.IsBool {
+		return s.Allows, nil
+	}
+	return s.Schema, nil
+}
+
 func (s *SchemaOrBool) UnmarshalYAML(value *yaml.Node) error {
 	if value.Kind == yaml.ScalarNode {
 		var b bool
@@ -212,10 +385,11 @@ func (s *SchemaOrBool) UnmarshalJSON(data []byte) error {
 
 // MediaType 表示媒体类型
 type MediaType struct {
-	Schema   *Schema             `json:"schema,omitempty" yaml:"schema,omitempty"`
-	Example  interface{}         `json:"example,omitempty" yaml:"example,omitempty"`
-	Examples map[string]Example  `json:"examples,omitempty" yaml:"examples,omitempty"`
-	Encoding map[string]Encoding `json:"encoding,omitempty" yaml:"encoding,omitempty"`
+	Schema     *Schema             `json:"schema,omitempty" yaml:"schema,omitempty"`
+	Example    interface{}         `json:"example,omitempty" yaml:"example,omitempty"`
+	Examples   map[string]Example  `json:"examples,omitempty" yaml:"examples,omitempty"`
+	Encoding   map[string]Encoding `json:"encoding,omitempty" yaml:"encoding,omitempty"`
+	Extensions Extensions          `json:"-" yaml:"-"`
 }
 
 // Example 表示示例
@@ -224,6 +398,7 @@ type Example struct {
 	Description   string      `json:"description,omitempty" yaml:"description,omitempty"`
 	Value         interface{} `json:"value,omitempty" yaml:"value,omitempty"`
 	ExternalValue string      `json:"externalValue,omitempty" yaml:"externalValue,omitempty"`
+	Extensions    Extensions  `json:"-" yaml:"-"`
 }
 
 // Header 表示头部
@@ -239,6 +414,8 @@ type Header struct {
 	Example         interface{}          `json:"example,omitempty" yaml:"example,omitempty"`
 	Examples        map[string]Example   `json:"examples,omitempty" yaml:"examples,omitempty"`
 	Content         map[string]MediaType `json:"content,omitempty" yaml:"content,omitempty"`
+	Extensions      Extensions           `json:"-" yaml:"-"`
+	Doc             *FieldDoc            `json:"-" yaml:"-"` // 由 AnnotateDocument 填充的注释解析结果
 }
 
 // Tag 表示标签
@@ -246,12 +423,15 @@ type Tag struct {
 	Name         string                 `json:"name" yaml:"name"`
 	Description  string                 `json:"description,omitempty" yaml:"description,omitempty"`
 	ExternalDocs *ExternalDocumentation `json:"externalDocs,omitempty" yaml:"externalDocs,omitempty"`
+	Extensions   Extensions             `json:"-" yaml:"-"`
+	Doc          *FieldDoc              `json:"-" yaml:"-"` // 由 AnnotateDocument 填充的注释解析结果
 }
 
 // ExternalDocumentation 表示外部文档
 type ExternalDocumentation struct {
-	Description string `json:"description,omitempty" yaml:"description,omitempty"`
-	URL         string `json:"url" yaml:"url"`
+	Description string     `json:"description,omitempty" yaml:"description,omitempty"`
+	URL         string     `json:"url" yaml:"url"`
+	Extensions  Extensions `json:"-" yaml:"-"`
 }
 
 // SecurityRequirement 表示安全要求
@@ -267,6 +447,7 @@ type SecurityScheme struct {
 	BearerFormat     string      `json:"bearerFormat,omitempty" yaml:"bearerFormat,omitempty"`
 	Flows            *OAuthFlows `json:"flows,omitempty" yaml:"flows,omitempty"`
 	OpenIDConnectURL string      `json:"openIdConnectUrl,omitempty" yaml:"openIdConnectUrl,omitempty"`
+	Extensions       Extensions  `json:"-" yaml:"-"`
 }
 
 // OAuthFlows 表示 OAuth 流程
@@ -275,6 +456,7 @@ type OAuthFlows struct {
 	Password          *OAuthFlow `json:"password,omitempty" yaml:"password,omitempty"`
 	ClientCredentials *OAuthFlow `json:"clientCredentials,omitempty" yaml:"clientCredentials,omitempty"`
 	AuthorizationCode *OAuthFlow `json:"authorizationCode,omitempty" yaml:"authorizationCode,omitempty"`
+	Extensions        Extensions `json:"-" yaml:"-"`
 }
 
 // OAuthFlow 表示 OAuth 流程
@@ -283,6 +465,7 @@ type OAuthFlow struct {
 	TokenURL         string            `json:"tokenUrl,omitempty" yaml:"tokenUrl,omitempty"`
 	RefreshURL       string            `json:"refreshUrl,omitempty" yaml:"refreshUrl,omitempty"`
 	Scopes           map[string]string `json:"scopes" yaml:"scopes"`
+	Extensions       Extensions        `json:"-" yaml:"-"`
 }
 
 // Link 表示链接
@@ -293,6 +476,7 @@ type Link struct {
 	RequestBody  interface{}            `json:"requestBody,omitempty" yaml:"requestBody,omitempty"`
 	Description  string                 `json:"description,omitempty" yaml:"description,omitempty"`
 	Server       *Server                `json:"server,omitempty" yaml:"server,omitempty"`
+	Extensions   Extensions             `json:"-" yaml:"-"`
 }
 
 // Callback 表示回调
@@ -300,17 +484,47 @@ type Callback map[string]PathItem
 
 // XML 表示 XML
 type XML struct {
-	Name      string `json:"name,omitempty" yaml:"name,omitempty"`
-	Namespace string `json:"namespace,omitempty" yaml:"namespace,omitempty"`
-	Prefix    string `json:"prefix,omitempty" yaml:"prefix,omitempty"`
-	Attribute bool   `json:"attribute,omitempty" yaml:"attribute,omitempty"`
-	Wrapped   bool   `json:"wrapped,omitempty" yaml:"wrapped,omitempty"`
+	Name       string     `json:"name,omitempty" yaml:"name,omitempty"`
+	Namespace  string     `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+	Prefix     string     `json:"prefix,omitempty" yaml:"prefix,omitempty"`
+	Attribute  bool       `json:"attribute,omitempty" yaml:"attribute,omitempty"`
+	Wrapped    bool       `json:"wrapped,omitempty" yaml:"wrapped,omitempty"`
+	Extensions Extensions `json:"-" yaml:"-"`
 }
 
 // Discriminator 表示鉴别器
 type Discriminator struct {
 	PropertyName string            `json:"propertyName" yaml:"propertyName"`
 	Mapping      map[string]string `json:"mapping,omitempty" yaml:"mapping,omitempty"`
+	Extensions   Extensions        `json:"-" yaml:"-"`
+}
+
+// discriminatorFields 用于在自定义反序列化中复用 Discriminator 的默认解码逻辑
+type discriminatorFields Discriminator
+
+func (d Discriminator) MarshalJSON() ([]byte, error) {
+	return marshalJSONExtensions(discriminatorFields(d), d.Extensions)
+}
+
+func (d Discriminator) MarshalYAML() (interface{}, error) {
+	return marshalYAMLExtensions(discriminatorFields(d), d.Extensions)
+}
+
+// UnmarshalYAML 兼容 Swagger 2.0 中 discriminator 仅为属性名字符串的写法
+func (d *Discriminator) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		return value.Decode(&d.PropertyName)
+	}
+	return unmarshalYAMLExtensions(value, (*discriminatorFields)(d), &d.Extensions)
+}
+
+// UnmarshalJSON 兼容 Swagger 2.0 中 discriminator 仅为属性名字符串的写法
+func (d *Discriminator) UnmarshalJSON(data []byte) error {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && trimmed[0] == '"' {
+		return json.Unmarshal(trimmed, &d.PropertyName)
+	}
+	return unmarshalJSONExtensions(trimmed, (*discriminatorFields)(d), &d.Extensions)
 }
 
 // Encoding 表示编码
@@ -320,6 +534,7 @@ type Encoding struct {
 	Style         string            `json:"style,omitempty" yaml:"style,omitempty"`
 	Explode       bool              `json:"explode,omitempty" yaml:"explode,omitempty"`
 	AllowReserved bool              `json:"allowReserved,omitempty" yaml:"allowReserved,omitempty"`
+	Extensions    Extensions        `json:"-" yaml:"-"`
 }
 
 // FieldDoc 表示字段文档信息
@@ -333,8 +548,11 @@ type FieldDoc struct {
 	FieldMinimum     int    `json:"fieldMinimum"`     // @minimum 后的内容
 	FieldMaximum     int    `json:"fieldMaximum"`     // @maximum 后的内容
 	FieldPattern     string `json:"fieldPattern"`     // @pattern 后的内容
-	FieldEnum        []int  `json:"fieldEnum"`        // @enum 后的内容
+	FieldEnum        []int  `json:"fieldEnum"`        // @enum 后的内容（仅当全部为整数时）
 	Raw              string `json:"raw"`              // 原始描述
+
+	FieldEnumValues []EnumValue    `json:"fieldEnumValues,omitempty"` // @enum 中的枚举值及其名称、说明
+	Annotations     *CommentParser `json:"-" yaml:"-"`                // 完整的解析结果，可读取任意标注
 }
 
 // OperationDescription 表示操作描述信息
@@ -345,4 +563,49 @@ type OperationDescription struct {
 	OperationID string            `json:"operationId,omitempty"`
 	Request     string            `json:"request,omitempty"`
 	Responses   map[string]string `json:"responses,omitempty"`
+
+	Annotations *CommentParser `json:"-" yaml:"-"` // 完整的解析结果，可读取任意标注
+}
+
+// httpMethods PathItem 支持的 HTTP 方法，按 Knife4j 菜单中常见的展示顺序排列
+var httpMethods = []string{"get", "post", "put", "patch", "delete"}
+
+// pathOperation 表示 PathItem 中某个 HTTP 方法对应的 Operation
+type pathOperation struct {
+	method    string
+	operation *Operation
+}
+
+// operations 按 httpMethods 顺序返回 PathItem 中已定义的 Operation
+func (p PathItem) operations() []pathOperation {
+	all := map[string]*Operation{
+		"get":    p.Get,
+		"post":   p.Post,
+		"put":    p.Put,
+		"patch":  p.Patch,
+		"delete": p.Delete,
+	}
+	result := make([]pathOperation, 0, len(all))
+	for _, method := range httpMethods {
+		if op := all[method]; op != nil {
+			result = append(result, pathOperation{method: method, operation: op})
+		}
+	}
+	return result
+}
+
+// removeOperation 删除 PathItem 中指定 HTTP 方法的 Operation
+func (p *PathItem) removeOperation(method string) {
+	switch method {
+	case "get":
+		p.Get = nil
+	case "post":
+		p.Post = nil
+	case "put":
+		p.Put = nil
+	case "patch":
+		p.Patch = nil
+	case "delete":
+		p.Delete = nil
+	}
 }
@@ -0,0 +1,310 @@
+package knife4g
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// OpenAPI30Version 降级后输出的 OpenAPI 版本号，与内置 Knife4j UI 支持的版本一致
+const OpenAPI30Version = "3.0.3"
+
+// DowngradeWarning 记录 OpenAPI 3.1 文档降级为 3.0 时无法等价映射的内容
+type DowngradeWarning struct {
+	Pointer string `json:"pointer"` // JSON Pointer，如 "#/components/schemas/Pet/examples"
+	Message string `json:"message"`
+}
+
+func (w DowngradeWarning) String() string {
+	return w.Pointer + ": " + w.Message
+}
+
+// DowngradeToOpenAPI30 将 OpenAPI 3.1 文档转换为内置 Knife4j UI 可渲染的 3.0 结构，返回新文档以及无法映射的内容列表。
+// 原文档保持不变；对 3.0 文档调用时返回其副本且不产生警告。
+//
+// 映射规则：
+//   - type 数组中的 "null" 转换为 nullable: true，剩余多个类型转换为 anyOf
+//   - 数值型 exclusiveMaximum/exclusiveMinimum 转换为 maximum/minimum + 布尔型 exclusive 标记
+//   - const 转换为单值 enum，examples 数组保留第一个值作为 example
+//   - contentEncoding: base64 转换为 format: byte，contentMediaType 转换为 format: binary
+//   - $defs 提升到 components.schemas，并改写指向它们的 $ref
+//   - webhooks 与 jsonSchemaDialect 在 3.0 中没有对应结构，直接丢弃
+func DowngradeToOpenAPI30(doc *OpenAPI3) (*OpenAPI3, []DowngradeWarning) {
+	if doc == nil {
+		return nil, nil
+	}
+
+	var warnings []DowngradeWarning
+	warn := func(pointer, format string, args ...any) {
+		warnings = append(warnings, DowngradeWarning{Pointer: pointer, Message: fmt.Sprintf(format, args...)})
+	}
+
+	defs := newDefsHoister(doc, warn)
+	result := rewriteDocumentSchemas(doc, func(pointer string, schema *Schema) *Schema {
+		return downgradeSchema(pointer, schema, defs, warn)
+	})
+
+	// 将 $defs 提升为 components.schemas
+	for _, name := range sortedKeys(defs.hoisted) {
+		result.Components.SetSchema(name, *defs.hoisted[name])
+	}
+
+	if doc.IsOpenAPI31() {
+		result.OpenAPI = OpenAPI30Version
+	}
+	if result.JSONSchemaDialect != "" {
+		warn("#/jsonSchemaDialect", "jsonSchemaDialect %q is not supported by OpenAPI 3.0 and was dropped", result.JSONSchemaDialect)
+		result.JSONSchemaDialect = ""
+	}
+	for _, name := range sortedKeys(result.Webhooks) {
+		warn("#/webhooks/"+escapePointer(name), "webhooks are not supported by OpenAPI 3.0 and were dropped")
+	}
+	result.Webhooks = nil
+
+	sort.SliceStable(warnings, func(i, j int) bool {
+		return warnings[i].Pointer < warnings[j].Pointer
+	})
+	return result, warnings
+}
+
+// downgradeSchema 将单个 Schema 节点中 3.1 专有的关键字改写为 3.0 等价形式
+func downgradeSchema(pointer string, schema *Schema, defs *defsHoister, warn func(pointer, format string, args ...any)) *Schema {
+	// type 数组
+	if len(schema.Type) > 1 || schema.Type.Is("null") {
+		types := make([]string, 0, len(schema.Type))
+		for _, t := range schema.Type {
+			if t == "null" {
+				schema.Nullable = true
+				continue
+			}
+			types = append(types, t)
+		}
+		switch len(types) {
+		case 0:
+			warn(pointer+"/type", "type \"null\" has no OpenAPI 3.0 equivalent, emitted as nullable without type")
+			schema.Type = nil
+		case 1:
+			schema.Type = SchemaType{types[0]}
+		default:
+			alternatives := make([]*Schema, len(types))
+			for i, t := range types {
+				alternatives[i] = &Schema{Type: SchemaType{t}}
+			}
+			if len(schema.AnyOf) > 0 {
+				schema.AllOf = append(schema.AllOf, &Schema{AnyOf: alternatives})
+			} else {
+				schema.AnyOf = alternatives
+			}
+			schema.Type = nil
+		}
+	}
+
+	// 数值型排他边界
+	if schema.ExclusiveMaximum != nil && schema.ExclusiveMaximum.IsNumber {
+		bound := schema.ExclusiveMaximum.Number
+		if schema.Maximum == nil || bound <= *schema.Maximum {
+			schema.Maximum = &bound
+			schema.ExclusiveMaximum = &BoolOrNumber{Bool: true}
+		} else {
+			schema.ExclusiveMaximum = nil
+		}
+	}
+	if schema.ExclusiveMinimum != nil && schema.ExclusiveMinimum.IsNumber {
+		bound := schema.ExclusiveMinimum.Number
+		if schema.Minimum == nil || bound >= *schema.Minimum {
+			schema.Minimum = &bound
+			schema.ExclusiveMinimum = &BoolOrNumber{Bool: true}
+		} else {
+			schema.ExclusiveMinimum = nil
+		}
+	}
+
+	// const
+	if schema.Const != nil {
+		if len(schema.Enum) == 0 {
+			schema.Enum = []interface{}{schema.Const}
+		} else {
+			warn(pointer+"/const", "const was dropped because enum is already present")
+		}
+		schema.Const = nil
+	}
+
+	// examples 数组
+	if len(schema.Examples) > 0 {
+		if schema.Example == nil {
+			schema.Example = schema.Examples[0]
+			if len(schema.Examples) > 1 {
+				warn(pointer+"/examples", "only the first of %d examples was kept", len(schema.Examples))
+			}
+		} else {
+			warn(pointer+"/examples", "examples were dropped because example is already present")
+		}
+		schema.Examples = nil
+	}
+
+	// contentEncoding / contentMediaType
+	switch {
+	case schema.ContentEncoding == "base64":
+		if schema.Format == "" {
+			schema.Format = "byte"
+		}
+		ensureStringType(schema)
+	case schema.ContentEncoding != "":
+		warn(pointer+"/contentEncoding", "contentEncoding %q has no OpenAPI 3.0 equivalent", schema.ContentEncoding)
+	case schema.ContentMediaType != "":
+		if schema.Format == "" {
+			schema.Format = ParamFormatBinary
+		}
+		ensureStringType(schema)
+	}
+	schema.ContentEncoding = ""
+	schema.ContentMediaType = ""
+
+	// $defs 提升到 components.schemas，引用在同一遍历中改写
+	for name, def := range schema.Defs {
+		defs.hoist(pointer+"/$defs/"+escapePointer(name), def)
+	}
+	schema.Defs = nil
+	if strings.Contains(schema.Ref, "/$defs/") {
+		schema.Ref = defs.ref(pointer, schema.Ref)
+	}
+	if schema.Discriminator != nil {
+		for value, ref := range schema.Discriminator.Mapping {
+			if strings.Contains(ref, "/$defs/") {
+				schema.Discriminator.Mapping[value] = defs.ref(pointer, ref)
+			}
+		}
+	}
+
+	return schema
+}
+
+// defsHoister 为文档中全部 $defs 条目分配 components.schemas 中的名称，并改写指向它们的 $ref。
+// 名称默认沿用条目名；与已有 Schema 或其他内容不同的同名条目冲突时加上所属 Schema 名称作为前缀，
+// 内容相同的同名条目合并为一个
+type defsHoister struct {
+	names   map[string]string   // $defs 条目的 JSON Pointer 到提升后的名称
+	owners  map[string][]string // 条目名到声明它的 Schema 的 JSON Pointer，按字典序排列
+	hoisted map[string]*Schema  // 提升后的名称到条目内容
+	warn    func(pointer, format string, args ...any)
+}
+
+func newDefsHoister(doc *OpenAPI3, warn func(pointer, format string, args ...any)) *defsHoister {
+	h := &defsHoister{
+		names:   make(map[string]string),
+		owners:  make(map[string][]string),
+		hoisted: make(map[string]*Schema),
+		warn:    warn,
+	}
+
+	// 先收集全部条目，按 JSON Pointer 的字典序分配名称，结果与 map 遍历顺序无关
+	type entry struct {
+		owner, name string
+		def         *Schema
+	}
+	entries := make(map[string]entry)
+	rewriteDocumentSchemas(doc, func(pointer string, schema *Schema) *Schema {
+		for name, def := range schema.Defs {
+			entries[pointer+"/$defs/"+escapePointer(name)] = entry{owner: pointer, name: name, def: def}
+		}
+		return schema
+	})
+
+	taken := make(map[string]bool, len(doc.Components.Schemas))
+	for name := range doc.Components.Schemas {
+		taken[name] = true
+	}
+	assigned := make(map[string][]string) // 条目名到已为其分配的名称
+	sources := make(map[string]*Schema)   // 已分配的名称到条目内容
+	for _, defPointer := range sortedKeys(entries) {
+		e := entries[defPointer]
+		h.owners[e.name] = append(h.owners[e.name], e.owner)
+		if shared := sharedDefName(assigned[e.name], sources, e.def); shared != "" {
+			h.names[defPointer] = shared
+			continue
+		}
+		name := e.name
+		if taken[name] {
+			base := defOwnerName(e.owner) + "_" + e.name
+			name = base
+			for i := 2; taken[name]; i++ {
+				name = base + strconv.Itoa(i)
+			}
+			warn(defPointer, "$defs entry %q conflicts with another schema of the same name and was renamed to %q", e.name, name)
+		}
+		taken[name] = true
+		assigned[e.name] = append(assigned[e.name], name)
+		sources[name] = e.def
+		h.names[defPointer] = name
+	}
+	return h
+}
+
+// sharedDefName 返回 names 中内容与 def 相同的名称，没有时返回空串
+func sharedDefName(names []string, sources map[string]*Schema, def *Schema) string {
+	for _, name := range names {
+		if reflect.DeepEqual(sources[name], def) {
+			return name
+		}
+	}
+	return ""
+}
+
+// hoist 记录降级后的 $defs 条目，defPointer 为其在原文档中的 JSON Pointer
+func (h *defsHoister) hoist(defPointer string, def *Schema) {
+	if name, ok := h.names[defPointer]; ok {
+		if _, exists := h.hoisted[name]; !exists {
+			h.hoisted[name] = def
+		}
+	}
+}
+
+// ref 将指向 $defs 条目（或其内部节点）的引用改写为指向 components.schemas。
+// "#/$defs/名称" 形式的引用按 JSON Schema 的就近原则解析为包含 pointer 的最内层同名条目
+func (h *defsHoister) ref(pointer, ref string) string {
+	idx := strings.Index(ref, "/$defs/")
+	owner := ref[:idx]
+	segment, rest, hasRest := strings.Cut(ref[idx+len("/$defs/"):], "/")
+
+	name, ok := "", false
+	if owner == "#" {
+		for candidate := pointer; !ok; {
+			name, ok = h.names[candidate+"/$defs/"+segment]
+			cut := strings.LastIndex(candidate, "/")
+			if cut < 0 {
+				break
+			}
+			candidate = candidate[:cut]
+		}
+		if !ok && len(h.owners[unescapePointer(segment)]) > 0 {
+			name, ok = h.names[h.owners[unescapePointer(segment)][0]+"/$defs/"+segment]
+		}
+	} else {
+		name, ok = h.names[owner+"/$defs/"+segment]
+	}
+	if !ok {
+		h.warn(pointer+"/$ref", "$ref %q does not point to a known $defs entry", ref)
+		name = unescapePointer(segment)
+	}
+	if hasRest {
+		return componentsSchemasPrefix + escapePointer(name) + "/" + rest
+	}
+	return componentsSchemasPrefix + escapePointer(name)
+}
+
+// defOwnerName 返回声明 $defs 的 Schema 的名称，用作冲突条目的前缀
+func defOwnerName(owner string) string {
+	if rest, ok := strings.CutPrefix(owner, componentsSchemasPrefix); ok {
+		segment, _, _ := strings.Cut(rest, "/")
+		return unescapePointer(segment)
+	}
+	return "Schema"
+}
+
+func ensureStringType(schema *Schema) {
+	if len(schema.Type) == 0 {
+		schema.Type = SchemaType{ParamTypeString}
+	}
+}
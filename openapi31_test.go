@@ -0,0 +1,117 @@
+package knife4g
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+// defsDoc 返回 3.1 文档：Order 与 Invoice 各自在 $defs 中声明 Address（内容相同）和 Pet（与同名组件不同）
+func defsDoc() *OpenAPI3 {
+	address := func() *Schema {
+		return objectSchema(map[string]*Schema{"city": stringSchema()})
+	}
+	owner := func(petEnum string) Schema {
+		return Schema{
+			Type: SchemaType{"object"},
+			Properties: map[string]*Schema{
+				"address": {Ref: "#/$defs/Address"},
+				"pet":     {Ref: "#/$defs/Pet"},
+				"city":    {Ref: "#/$defs/Address/properties/city"},
+				"slash":   {Ref: "#/$defs/a~1b"},
+			},
+			Defs: map[string]*Schema{
+				"Address": address(),
+				"Pet":     stringSchema(petEnum),
+				"a/b":     stringSchema(),
+			},
+		}
+	}
+	doc := schemaDoc(refSchema("Order"), nil)
+	doc.OpenAPI = "3.1.0"
+	doc.Components.SetSchema("Pet", *objectSchema(map[string]*Schema{"name": stringSchema()}))
+	doc.Components.SetSchema("Order", owner("dog"))
+	doc.Components.SetSchema("Invoice", owner("cat"))
+	return doc
+}
+
+func TestDowngradeHoistsDefs(t *testing.T) {
+	doc := defsDoc()
+	order := append([]string(nil), doc.Components.schemaOrder...)
+
+	result, warnings := DowngradeToOpenAPI30(doc)
+
+	wantRefs := map[string]map[string]string{
+		"Order": {
+			"address": "#/components/schemas/Address",
+			"pet":     "#/components/schemas/Order_Pet",
+			"city":    "#/components/schemas/Address/properties/city",
+			"slash":   "#/components/schemas/a~1b",
+		},
+		"Invoice": {
+			"address": "#/components/schemas/Address",
+			"pet":     "#/components/schemas/Invoice_Pet",
+			"city":    "#/components/schemas/Address/properties/city",
+			"slash":   "#/components/schemas/a~1b",
+		},
+	}
+	for owner, refs := range wantRefs {
+		schema := result.Components.Schemas[owner]
+		if schema.Defs != nil {
+			t.Errorf("%s: $defs were not removed", owner)
+		}
+		for property, want := range refs {
+			if got := schema.Properties[property].Ref; got != want {
+				t.Errorf("%s.%s: $ref = %q, want %q", owner, property, got, want)
+			}
+		}
+	}
+
+	wantOrder := []string{"Pet", "Order", "Invoice", "Address", "Invoice_Pet", "Order_Pet", "a/b"}
+	if got := result.Components.OrderedSchemas(); !reflect.DeepEqual(got, wantOrder) {
+		t.Errorf("schemas = %q, want %q", got, wantOrder)
+	}
+	if got := result.Components.Schemas["Pet"].Properties["name"]; got == nil {
+		t.Error("component Pet was overwritten by a $defs entry")
+	}
+	if got := result.Components.Schemas["Order_Pet"].Enum; !reflect.DeepEqual(got, []interface{}{"dog"}) {
+		t.Errorf("Order_Pet enum = %v, want [dog]", got)
+	}
+	if got := result.Components.Schemas["Invoice_Pet"].Enum; !reflect.DeepEqual(got, []interface{}{"cat"}) {
+		t.Errorf("Invoice_Pet enum = %v, want [cat]", got)
+	}
+
+	wantWarnings := []string{
+		"#/components/schemas/Invoice/$defs/Pet",
+		"#/components/schemas/Order/$defs/Pet",
+	}
+	var gotWarnings []string
+	for _, w := range warnings {
+		gotWarnings = append(gotWarnings, w.Pointer)
+	}
+	if !reflect.DeepEqual(gotWarnings, wantWarnings) {
+		t.Errorf("warnings = %+v, want pointers %q", warnings, wantWarnings)
+	}
+
+	if !reflect.DeepEqual(doc.Components.schemaOrder, order) {
+		t.Errorf("source schema order changed to %q", doc.Components.schemaOrder)
+	}
+}
+
+func TestDowngradeDefsDeterministic(t *testing.T) {
+	first, _ := DowngradeToOpenAPI30(defsDoc())
+	want, err := json.Marshal(first)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 20; i++ {
+		result, _ := DowngradeToOpenAPI30(defsDoc())
+		got, err := json.Marshal(result)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != string(want) {
+			t.Fatalf("run %d differs:\n%s\nwant\n%s", i, got, want)
+		}
+	}
+}
@@ -0,0 +1,309 @@
+package knife4g
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// yamlMemberKeys 按源文件顺序返回 YAML 映射节点中 member 子映射的全部键
+func yamlMemberKeys(value *yaml.Node, member string) []string {
+	if value.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(value.Content); i += 2 {
+		if value.Content[i].Value != member {
+			continue
+		}
+		child := value.Content[i+1]
+		if child.Kind != yaml.MappingNode {
+			return nil
+		}
+		keys := make([]string, 0, len(child.Content)/2)
+		for j := 0; j+1 < len(child.Content); j += 2 {
+			keys = append(keys, child.Content[j].Value)
+		}
+		return keys
+	}
+	return nil
+}
+
+// jsonMemberKeys 按源文件顺序返回 JSON 对象中 member 子对象的全部键
+func jsonMemberKeys(data []byte, member string) []string {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil
+	}
+	child, ok := raw[member]
+	if !ok {
+		return nil
+	}
+	return jsonObjectKeys(child)
+}
+
+// jsonObjectKeys 按源文件顺序返回 JSON 对象的顶层键
+func jsonObjectKeys(data []byte) []string {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+		return nil
+	}
+	var keys []string
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return keys
+		}
+		key, ok := token.(string)
+		if !ok {
+			return keys
+		}
+		keys = append(keys, key)
+		// 跳过对应的值
+		var skip json.RawMessage
+		if err := decoder.Decode(&skip); err != nil {
+			return keys
+		}
+	}
+	return keys
+}
+
+// orderedKeys 按 order 给出的顺序返回 m 的键，order 中缺失的键按字母序追加在末尾
+func orderedKeys[V any](m map[string]V, order []string) []string {
+	keys := make([]string, 0, len(m))
+	seen := make(map[string]bool, len(m))
+	for _, key := range order {
+		if _, exists := m[key]; exists && !seen[key] {
+			keys = append(keys, key)
+			seen[key] = true
+		}
+	}
+	rest := make([]string, 0, len(m)-len(keys))
+	for key := range m {
+		if !seen[key] {
+			rest = append(rest, key)
+		}
+	}
+	sort.Strings(rest)
+	return append(keys, rest...)
+}
+
+// OrderedPaths 按源文件中的出现顺序返回文档的全部路径，无法确定顺序的路径按字母序排在末尾
+func (o *OpenAPI3) OrderedPaths() []string {
+	return orderedKeys(o.Paths, o.pathOrder)
+}
+
+// OrderedProperties 按源文件中的出现顺序返回 Schema 的属性名
+func (s *Schema) OrderedProperties() []string {
+	return orderedKeys(s.Properties, s.propertyOrder)
+}
+
+// OrderedSchemas 按源文件中的出现顺序返回 components.schemas 的名称
+func (c *Components) OrderedSchemas() []string {
+	return orderedKeys(c.Schemas, c.schemaOrder)
+}
+
+// OrderedResponses 按源文件中的出现顺序返回操作的响应状态码
+func (o *Operation) OrderedResponses() []string {
+	return orderedKeys(o.Responses, o.responseOrder)
+}
+
+// OrderedContentTypes 按源文件中的出现顺序返回请求体的媒体类型
+func (r *RequestBody) OrderedContentTypes() []string {
+	return orderedKeys(r.Content, r.contentOrder)
+}
+
+// OrderedContentTypes 按源文件中的出现顺序返回响应的媒体类型
+func (r *Response) OrderedContentTypes() []string {
+	return orderedKeys(r.Content, r.contentOrder)
+}
+
+// OrderedHeaders 按源文件中的出现顺序返回响应头名称
+func (r *Response) OrderedHeaders() []string {
+	return orderedKeys(r.Headers, r.headerOrder)
+}
+
+// OrderedLinks 按源文件中的出现顺序返回响应链接名称
+func (r *Response) OrderedLinks() []string {
+	return orderedKeys(r.Links, r.linkOrder)
+}
+
+// SetPath 设置路径项并记录其顺序，以代码构建文档时用于保留路径的声明顺序
+func (o *OpenAPI3) SetPath(path string, item PathItem) {
+	if o.Paths == nil {
+		o.Paths = make(map[string]PathItem)
+	}
+	if _, exists := o.Paths[path]; !exists {
+		o.pathOrder = append(o.pathOrder, path)
+	}
+	o.Paths[path] = item
+}
+
+// SetProperty 设置 Schema 属性并记录其顺序，以代码构建文档时用于保留字段的声明顺序
+func (s *Schema) SetProperty(name string, property *Schema) {
+	if s.Properties == nil {
+		s.Properties = make(map[string]*Schema)
+	}
+	if _, exists := s.Properties[name]; !exists {
+		s.propertyOrder = append(s.propertyOrder, name)
+	}
+	s.Properties[name] = property
+}
+
+// SetSchema 设置 components.schemas 中的 Schema 并记录其顺序
+func (c *Components) SetSchema(name string, schema Schema) {
+	if c.Schemas == nil {
+		c.Schemas = make(map[string]Schema)
+	}
+	if _, exists := c.Schemas[name]; !exists {
+		c.schemaOrder = append(c.schemaOrder, name)
+	}
+	c.Schemas[name] = schema
+}
+
+// reorderYAMLMember 按 order 重排 YAML 映射节点中 member 子映射的键，使序列化结果保留源文件或构建时的顺序
+func reorderYAMLMember(value interface{}, member string, order []string) {
+	node, ok := value.(*yaml.Node)
+	if !ok || len(order) == 0 || node.Kind != yaml.MappingNode {
+		return
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value != member {
+			continue
+		}
+		child := node.Content[i+1]
+		if child.Kind != yaml.MappingNode {
+			return
+		}
+		pairs := make(map[string][]*yaml.Node, len(child.Content)/2)
+		for j := 0; j+1 < len(child.Content); j += 2 {
+			pairs[child.Content[j].Value] = child.Content[j : j+2]
+		}
+		content := make([]*yaml.Node, 0, len(child.Content))
+		for _, key := range orderedKeys(pairs, order) {
+			content = append(content, pairs[key]...)
+		}
+		child.Content = content
+		return
+	}
+}
+
+// reorderJSONMember 按 order 重排 JSON 对象中 member 子对象的键，与 reorderYAMLMember 对应。
+// data 须为 encoding/json 输出的紧凑格式，其余键保持原有位置
+func reorderJSONMember(data []byte, member string, order []string) ([]byte, error) {
+	if len(order) == 0 {
+		return data, nil
+	}
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	child, ok := raw[member]
+	if !ok {
+		return data, nil
+	}
+	var pairs map[string]json.RawMessage
+	if err := json.Unmarshal(child, &pairs); err != nil || pairs == nil {
+		return data, nil
+	}
+	raw[member] = writeJSONObject(orderedKeys(pairs, order), pairs)
+	return writeJSONObject(jsonObjectKeys(data), raw), nil
+}
+
+// writeJSONObject 按 keys 的顺序输出由已编码的值组成的 JSON 对象
+func writeJSONObject(keys []string, values map[string]json.RawMessage) []byte {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, _ := json.Marshal(key)
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(values[key])
+	}
+	buf.WriteByte('}')
+	return buf.Bytes()
+}
+
+// orderedMap 按插入顺序输出键的 JSON/YAML 对象，用于生成字节稳定的文档
+type orderedMap struct {
+	keys   []string
+	values map[string]any
+}
+
+func newOrderedMap() *orderedMap {
+	return &orderedMap{values: make(map[string]any)}
+}
+
+// Set 写入键值，已存在的键保持原有位置
+func (m *orderedMap) Set(key string, value any) {
+	if _, exists := m.values[key]; !exists {
+		m.keys = append(m.keys, key)
+	}
+	m.values[key] = value
+}
+
+func (m *orderedMap) Get(key string) (any, bool) {
+	value, exists := m.values[key]
+	return value, exists
+}
+
+func (m *orderedMap) Has(key string) bool {
+	_, exists := m.values[key]
+	return exists
+}
+
+func (m *orderedMap) Delete(key string) {
+	if _, exists := m.values[key]; !exists {
+		return
+	}
+	delete(m.values, key)
+	for i, k := range m.keys {
+		if k == key {
+			m.keys = append(m.keys[:i], m.keys[i+1:]...)
+			break
+		}
+	}
+}
+
+func (m *orderedMap) Len() int {
+	return len(m.keys)
+}
+
+func (m *orderedMap) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range m.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(m.values[key])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+func (m *orderedMap) MarshalYAML() (interface{}, error) {
+	node := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	for _, key := range m.keys {
+		var valueNode yaml.Node
+		if err := valueNode.Encode(m.values[key]); err != nil {
+			return nil, err
+		}
+		node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, &valueNode)
+	}
+	return node, nil
+}
@@ -0,0 +1,170 @@
+package knife4g
+
+import (
+	"errors"
+	"strings"
+)
+
+// ExtOneOf 标记 Proto oneof 成员字段所属的分组名称，写在 properties 中的各成员字段上
+const ExtOneOf = "x-oneof"
+
+// componentSchemaRef 将 Schema 名称转换为 components.schemas 引用，已是引用时原样返回
+func componentSchemaRef(name string) string {
+	if strings.HasPrefix(name, "#/") {
+		return name
+	}
+	return componentsSchemasPrefix + name
+}
+
+// parseDiscriminatorAnnotation 解析 @discriminator 标注的值，格式为 "属性名" 或 "属性名 | 取值=类型名, 取值=类型名"
+func parseDiscriminatorAnnotation(value string) (propertyName string, mapping [][2]string, err error) {
+	propertyName, rest, _ := strings.Cut(value, "|")
+	propertyName = strings.TrimSpace(propertyName)
+	if propertyName == "" {
+		return "", nil, errors.New(`expected "<property> [| value=Schema, ...]"`)
+	}
+	for _, item := range strings.Split(rest, ",") {
+		if item = strings.TrimSpace(item); item == "" {
+			continue
+		}
+		key, name, found := strings.Cut(item, "=")
+		key, name = strings.TrimSpace(key), strings.TrimSpace(name)
+		if !found || key == "" || name == "" {
+			return "", nil, errors.New(`mapping entries must look like "value=Schema"`)
+		}
+		mapping = append(mapping, [2]string{key, componentSchemaRef(name)})
+	}
+	return propertyName, mapping, nil
+}
+
+// convertDiscriminatorToOpenAPI3 将 Discriminator 转换为 OpenAPI 3.0 格式，mapping 按取值排序
+func convertDiscriminatorToOpenAPI3(discriminator *Discriminator) *orderedMap {
+	result := newOrderedMap()
+	result.Set("propertyName", discriminator.PropertyName)
+	if len(discriminator.Mapping) > 0 {
+		mapping := newOrderedMap()
+		for _, key := range sortedKeys(discriminator.Mapping) {
+			mapping.Set(key, discriminator.Mapping[key])
+		}
+		result.Set("mapping", mapping)
+	}
+	copyExtensions(result, discriminator.Extensions)
+	return result
+}
+
+// applyPolymorphismAnnotations 将 @oneOf、@anyOf 与 @discriminator 标注写入输出结构，覆盖 Schema 自身的同名字段
+func applyPolymorphismAnnotations(result *orderedMap, parser *CommentParser) {
+	for _, tag := range []string{"oneOf", "anyOf"} {
+		names := parser.GetArray(tag)
+		if len(names) == 0 {
+			continue
+		}
+		refs := make([]*orderedMap, len(names))
+		for i, name := range names {
+			refs[i] = newOrderedMap()
+			refs[i].Set("$ref", componentSchemaRef(name))
+		}
+		result.Set(tag, refs)
+	}
+
+	if !parser.HasTag("discriminator") {
+		return
+	}
+	propertyName, mapping, err := parseDiscriminatorAnnotation(parser.GetString("discriminator"))
+	if err != nil {
+		return
+	}
+	discriminator := newOrderedMap()
+	discriminator.Set("propertyName", propertyName)
+	if len(mapping) > 0 {
+		mappingMap := newOrderedMap()
+		for _, entry := range mapping {
+			mappingMap.Set(entry[0], entry[1])
+		}
+		discriminator.Set("mapping", mappingMap)
+	}
+	result.Set("discriminator", discriminator)
+}
+
+// applyOneofGroups 将带 x-oneof 扩展的 Proto oneof 成员字段转换为 oneOf 备选项：
+// 成员字段仍保留在 properties 中供 Knife4j 展示，并在描述末尾标注所属分组；
+// 每个分组输出为 "oneOf: [{required: [a]}, {required: [b]}, {not: {anyOf: [{required: [a]}, {required: [b]}]}}]"，
+// 最后一项为未设置任何成员的空备选，因此至多设置一个成员，未设置的 oneof 同样合法。
+// 第一个分组直接写入 oneOf，其余分组或 Schema 已有 oneOf 时以 allOf 组合
+func applyOneofGroups(result *orderedMap, schema *Schema) {
+	if len(schema.Properties) == 0 {
+		return
+	}
+	var groups []string
+	members := make(map[string][]string)
+	for _, name := range schema.OrderedProperties() {
+		prop := schema.Properties[name]
+		if prop == nil {
+			continue
+		}
+		group := prop.Extensions.GetString(ExtOneOf)
+		if group == "" {
+			continue
+		}
+		if _, exists := members[group]; !exists {
+			groups = append(groups, group)
+		}
+		members[group] = append(members[group], name)
+	}
+	if len(groups) == 0 {
+		return
+	}
+
+	properties, _ := result.Get("properties")
+	propertiesMap, _ := properties.(*orderedMap)
+	for _, group := range groups {
+		names := members[group]
+		if propertiesMap != nil {
+			for _, name := range names {
+				if member, ok := propertiesMap.values[name].(*orderedMap); ok {
+					appendBadge(member, "oneof "+group)
+				}
+			}
+		}
+		if len(names) < 2 {
+			continue
+		}
+
+		alternatives := make([]*orderedMap, 0, len(names)+1)
+		required := make([]*orderedMap, len(names))
+		for i, name := range names {
+			alternative := newOrderedMap()
+			alternative.Set("title", name)
+			alternative.Set("required", []string{name})
+			alternatives = append(alternatives, alternative)
+			required[i] = newOrderedMap()
+			required[i].Set("required", []string{name})
+		}
+		anyMember := newOrderedMap()
+		anyMember.Set("anyOf", required)
+		unset := newOrderedMap()
+		unset.Set("title", "none")
+		unset.Set("not", anyMember)
+		alternatives = append(alternatives, unset)
+
+		if !result.Has("oneOf") {
+			result.Set("oneOf", alternatives)
+			continue
+		}
+		constraint := newOrderedMap()
+		constraint.Set("oneOf", alternatives)
+		allOf, _ := result.Get("allOf")
+		combined, _ := allOf.([]*orderedMap)
+		result.Set("allOf", append(combined, constraint))
+	}
+}
+
+// appendBadge 在描述末尾追加一个 Markdown 行内代码样式的徽标
+func appendBadge(target *orderedMap, label string) {
+	description, _ := target.Get("description")
+	text, _ := description.(string)
+	if text != "" {
+		text += "\n\n"
+	}
+	target.Set("description", text+"`"+label+"`")
+}
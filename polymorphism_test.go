@@ -0,0 +1,204 @@
+package knife4g
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestParseDiscriminatorAnnotation(t *testing.T) {
+	tests := []struct {
+		value       string
+		wantName    string
+		wantMapping [][2]string
+		wantErr     bool
+	}{
+		{value: "type", wantName: "type"},
+		{
+			value:    "type | card=CardPayment, bank=#/components/schemas/Bank",
+			wantName: "type",
+			wantMapping: [][2]string{
+				{"card", "#/components/schemas/CardPayment"},
+				{"bank", "#/components/schemas/Bank"},
+			},
+		},
+		{value: "type | card=CardPayment,", wantName: "type", wantMapping: [][2]string{{"card", "#/components/schemas/CardPayment"}}},
+		{value: " | card=CardPayment", wantErr: true},
+		{value: "type | card", wantErr: true},
+		{value: "type | =CardPayment", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			name, mapping, err := parseDiscriminatorAnnotation(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if name != tt.wantName || !reflect.DeepEqual(mapping, tt.wantMapping) {
+				t.Errorf("got %q, %q, want %q, %q", name, mapping, tt.wantName, tt.wantMapping)
+			}
+		})
+	}
+}
+
+func TestPolymorphismAnnotations(t *testing.T) {
+	schema := &Schema{
+		Description: "支付方式\n@oneOf: CardPayment, BankPayment\n@discriminator: type | card=CardPayment, bank=BankPayment",
+		OneOf:       []*Schema{refSchema("Legacy")},
+	}
+	want := `{
+		"oneOf": [{"$ref": "#/components/schemas/CardPayment"}, {"$ref": "#/components/schemas/BankPayment"}],
+		"discriminator": {
+			"propertyName": "type",
+			"mapping": {"card": "#/components/schemas/CardPayment", "bank": "#/components/schemas/BankPayment"}
+		}
+	}`
+	assertSchemaKeys(t, convertSchemaToOpenAPI3(schema, nil), want, "oneOf", "discriminator")
+}
+
+// oneofSchema 返回含两个 oneof 分组的消息：payment 有 card、bank 两个成员，contact 有 email、phone 两个成员，
+// solo 只有一个成员
+func oneofSchema() *Schema {
+	member := func(group string) *Schema {
+		return &Schema{Type: SchemaType{"string"}, Extensions: Extensions{ExtOneOf: group}}
+	}
+	schema := &Schema{Type: SchemaType{"object"}}
+	schema.SetProperty("id", stringSchema())
+	schema.SetProperty("card", member("payment"))
+	schema.SetProperty("email", member("contact"))
+	schema.SetProperty("bank", member("payment"))
+	schema.SetProperty("phone", member("contact"))
+	schema.SetProperty("nickname", member("solo"))
+	return schema
+}
+
+func TestApplyOneofGroups(t *testing.T) {
+	result := convertSchemaToOpenAPI3(oneofSchema(), nil)
+
+	want := `{
+		"oneOf": [
+			{"title": "card", "required": ["card"]},
+			{"title": "bank", "required": ["bank"]},
+			{"title": "none", "not": {"anyOf": [{"required": ["card"]}, {"required": ["bank"]}]}}
+		],
+		"allOf": [{"oneOf": [
+			{"title": "email", "required": ["email"]},
+			{"title": "phone", "required": ["phone"]},
+			{"title": "none", "not": {"anyOf": [{"required": ["email"]}, {"required": ["phone"]}]}}
+		]}]
+	}`
+	assertSchemaKeys(t, result, want, "oneOf", "allOf")
+	if result.Has("not") {
+		t.Error("unexpected not constraint")
+	}
+
+	badges := map[string]string{
+		"card":     "`oneof payment`",
+		"bank":     "`oneof payment`",
+		"email":    "`oneof contact`",
+		"nickname": "`oneof solo`",
+	}
+	for name, want := range badges {
+		description, _ := orderedLookup(result, "properties", name).Get("description")
+		if description != want {
+			t.Errorf("%s description = %q, want %q", name, description, want)
+		}
+	}
+	if orderedLookup(result, "properties", "id").Has("description") {
+		t.Error("id got a oneof badge")
+	}
+}
+
+func TestApplyOneofGroupsWithOwnOneOf(t *testing.T) {
+	schema := oneofSchema()
+	schema.OneOf = []*Schema{refSchema("Legacy")}
+	result := convertSchemaToOpenAPI3(schema, nil)
+
+	assertSchemaKeys(t, result, `{"oneOf": [{"$ref": "#/components/schemas/Legacy"}]}`, "oneOf")
+	allOf, _ := result.Get("allOf")
+	if groups, _ := allOf.([]*orderedMap); len(groups) != 2 {
+		t.Errorf("allOf has %d entries, want one per group", len(groups))
+	}
+}
+
+// TestOneofGroupSemantics 按 JSON Schema 的 oneOf 语义校验分组约束：不设置或只设置一个成员合法，设置多个成员非法
+func TestOneofGroupSemantics(t *testing.T) {
+	result := convertSchemaToOpenAPI3(oneofSchema(), nil)
+	value, _ := result.Get("oneOf")
+	alternatives := value.([]*orderedMap)
+
+	tests := []struct {
+		set   []string
+		valid bool
+	}{
+		{nil, true},
+		{[]string{"card"}, true},
+		{[]string{"bank"}, true},
+		{[]string{"card", "bank"}, false},
+		{[]string{"email", "card"}, true},
+	}
+	for _, tt := range tests {
+		present := make(map[string]bool)
+		for _, name := range tt.set {
+			present[name] = true
+		}
+		matches := 0
+		for _, alternative := range alternatives {
+			if matchesRequired(alternative, present) {
+				matches++
+			}
+		}
+		if valid := matches == 1; valid != tt.valid {
+			t.Errorf("members %q: %d alternatives match, valid = %v, want %v", tt.set, matches, valid, tt.valid)
+		}
+	}
+}
+
+// matchesRequired 求值只由 required、not 与 anyOf 组成的约束
+func matchesRequired(constraint *orderedMap, present map[string]bool) bool {
+	if value, ok := constraint.Get("required"); ok {
+		for _, name := range value.([]string) {
+			if !present[name] {
+				return false
+			}
+		}
+	}
+	if value, ok := constraint.Get("not"); ok && matchesRequired(value.(*orderedMap), present) {
+		return false
+	}
+	if value, ok := constraint.Get("anyOf"); ok {
+		for _, alternative := range value.([]*orderedMap) {
+			if matchesRequired(alternative, present) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// assertSchemaKeys 比较转换结果中 keys 对应的值与 want 中的同名字段
+func assertSchemaKeys(t *testing.T, result *orderedMap, want string, keys ...string) {
+	t.Helper()
+	var expected map[string]any
+	if err := json.Unmarshal([]byte(want), &expected); err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got map[string]any
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range keys {
+		if !reflect.DeepEqual(got[key], expected[key]) {
+			gotJSON, _ := json.Marshal(got[key])
+			wantJSON, _ := json.Marshal(expected[key])
+			t.Errorf("%s = %s\nwant %s", key, gotJSON, wantJSON)
+		}
+	}
+}
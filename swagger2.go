@@ -0,0 +1,1232 @@
+package knife4g
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// Swagger 2.0 参数位置常量
+	ParamInBody = "body"
+
+	swagger2DefinitionsPrefix = "#/definitions/"
+	swagger2ParametersPrefix  = "#/parameters/"
+	swagger2ResponsesPrefix   = "#/responses/"
+	componentsSchemasPrefix   = "#/components/schemas/"
+)
+
+// swagger2Doc 表示 Swagger 2.0 文档结构，仅用于与 OpenAPI3 之间的相互转换
+type swagger2Doc struct {
+	Swagger             string                            `json:"swagger" yaml:"swagger"`
+	Info                Info                              `json:"info" yaml:"info"`
+	Host                string                            `json:"host,omitempty" yaml:"host,omitempty"`
+	BasePath            string                            `json:"basePath,omitempty" yaml:"basePath,omitempty"`
+	Schemes             []string                          `json:"schemes,omitempty" yaml:"schemes,omitempty"`
+	Consumes            []string                          `json:"consumes,omitempty" yaml:"consumes,omitempty"`
+	Produces            []string                          `json:"produces,omitempty" yaml:"produces,omitempty"`
+	Paths               map[string]swagger2PathItem       `json:"paths" yaml:"paths"`
+	Definitions         map[string]Schema                 `json:"definitions,omitempty" yaml:"definitions,omitempty"`
+	Parameters          map[string]swagger2Parameter      `json:"parameters,omitempty" yaml:"parameters,omitempty"`
+	Responses           map[string]swagger2Response       `json:"responses,omitempty" yaml:"responses,omitempty"`
+	SecurityDefinitions map[string]swagger2SecurityScheme `json:"securityDefinitions,omitempty" yaml:"securityDefinitions,omitempty"`
+	Security            []SecurityRequirement             `json:"security,omitempty" yaml:"security,omitempty"`
+	Tags                []Tag                             `json:"tags,omitempty" yaml:"tags,omitempty"`
+	ExternalDocs        *ExternalDocumentation            `json:"externalDocs,omitempty" yaml:"externalDocs,omitempty"`
+	Extensions          Extensions                        `json:"-" yaml:"-"`
+}
+
+// swagger2PathItem 表示 Swagger 2.0 路径项
+type swagger2PathItem struct {
+	Ref        string              `json:"$ref,omitempty" yaml:"$ref,omitempty"`
+	Get        *swagger2Operation  `json:"get,omitempty" yaml:"get,omitempty"`
+	Put        *swagger2Operation  `json:"put,omitempty" yaml:"put,omitempty"`
+	Post       *swagger2Operation  `json:"post,omitempty" yaml:"post,omitempty"`
+	Delete     *swagger2Operation  `json:"delete,omitempty" yaml:"delete,omitempty"`
+	Patch      *swagger2Operation  `json:"patch,omitempty" yaml:"patch,omitempty"`
+	Parameters []swagger2Parameter `json:"parameters,omitempty" yaml:"parameters,omitempty"`
+	Extensions Extensions          `json:"-" yaml:"-"`
+}
+
+// swagger2Operation 表示 Swagger 2.0 操作
+type swagger2Operation struct {
+	Tags        []string                    `json:"tags,omitempty" yaml:"tags,omitempty"`
+	Summary     string                      `json:"summary,omitempty" yaml:"summary,omitempty"`
+	Description string                      `json:"description,omitempty" yaml:"description,omitempty"`
+	OperationID string                      `json:"operationId,omitempty" yaml:"operationId,omitempty"`
+	Consumes    []string                    `json:"consumes,omitempty" yaml:"consumes,omitempty"`
+	Produces    []string                    `json:"produces,omitempty" yaml:"produces,omitempty"`
+	Parameters  []swagger2Parameter         `json:"parameters,omitempty" yaml:"parameters,omitempty"`
+	Responses   map[string]swagger2Response `json:"responses" yaml:"responses"`
+	Schemes     []string                    `json:"schemes,omitempty" yaml:"schemes,omitempty"`
+	Deprecated  bool                        `json:"deprecated,omitempty" yaml:"deprecated,omitempty"`
+	Security    []SecurityRequirement       `json:"security,omitempty" yaml:"security,omitempty"`
+	Extensions  Extensions                  `json:"-" yaml:"-"`
+}
+
+// swagger2Items 表示 Swagger 2.0 非 body 参数与响应头使用的简化 Schema
+type swagger2Items struct {
+	Type             string         `json:"type,omitempty" yaml:"type,omitempty"`
+	Format           string         `json:"format,omitempty" yaml:"format,omitempty"`
+	Items            *swagger2Items `json:"items,omitempty" yaml:"items,omitempty"`
+	CollectionFormat string         `json:"collectionFormat,omitempty" yaml:"collectionFormat,omitempty"`
+	Default          interface{}    `json:"default,omitempty" yaml:"default,omitempty"`
+	Maximum          *float64       `json:"maximum,omitempty" yaml:"maximum,omitempty"`
+	ExclusiveMaximum bool           `json:"exclusiveMaximum,omitempty" yaml:"exclusiveMaximum,omitempty"`
+	Minimum          *float64       `json:"minimum,omitempty" yaml:"minimum,omitempty"`
+	ExclusiveMinimum bool           `json:"exclusiveMinimum,omitempty" yaml:"exclusiveMinimum,omitempty"`
+	MaxLength        *int           `json:"maxLength,omitempty" yaml:"maxLength,omitempty"`
+	MinLength        *int           `json:"minLength,omitempty" yaml:"minLength,omitempty"`
+	Pattern          string         `json:"pattern,omitempty" yaml:"pattern,omitempty"`
+	MaxItems         *int           `json:"maxItems,omitempty" yaml:"maxItems,omitempty"`
+	MinItems         *int           `json:"minItems,omitempty" yaml:"minItems,omitempty"`
+	UniqueItems      bool           `json:"uniqueItems,omitempty" yaml:"uniqueItems,omitempty"`
+	Enum             []interface{}  `json:"enum,omitempty" yaml:"enum,omitempty"`
+	MultipleOf       *float64       `json:"multipleOf,omitempty" yaml:"multipleOf,omitempty"`
+}
+
+// swagger2Parameter 表示 Swagger 2.0 参数，in 为 body 时使用 Schema，其余位置使用内联的 swagger2Items 字段
+type swagger2Parameter struct {
+	Ref             string  `json:"$ref,omitempty" yaml:"$ref,omitempty"`
+	Name            string  `json:"name,omitempty" yaml:"name,omitempty"`
+	In              string  `json:"in,omitempty" yaml:"in,omitempty"`
+	Description     string  `json:"description,omitempty" yaml:"description,omitempty"`
+	Required        bool    `json:"required,omitempty" yaml:"required,omitempty"`
+	AllowEmptyValue bool    `json:"allowEmptyValue,omitempty" yaml:"allowEmptyValue,omitempty"`
+	Schema          *Schema `json:"schema,omitempty" yaml:"schema,omitempty"`
+	swagger2Items   `yaml:",inline"`
+	Extensions      Extensions `json:"-" yaml:"-"`
+}
+
+// swagger2Response 表示 Swagger 2.0 响应
+type swagger2Response struct {
+	Ref         string                    `json:"$ref,omitempty" yaml:"$ref,omitempty"`
+	Description string                    `json:"description" yaml:"description"`
+	Schema      *Schema                   `json:"schema,omitempty" yaml:"schema,omitempty"`
+	Headers     map[string]swagger2Header `json:"headers,omitempty" yaml:"headers,omitempty"`
+	Examples    map[string]interface{}    `json:"examples,omitempty" yaml:"examples,omitempty"`
+	Extensions  Extensions                `json:"-" yaml:"-"`
+}
+
+// swagger2Header 表示 Swagger 2.0 响应头
+type swagger2Header struct {
+	Description   string `json:"description,omitempty" yaml:"description,omitempty"`
+	swagger2Items `yaml:",inline"`
+	Extensions    Extensions `json:"-" yaml:"-"`
+}
+
+// swagger2SecurityScheme 表示 Swagger 2.0 安全定义
+type swagger2SecurityScheme struct {
+	Type             string            `json:"type" yaml:"type"`
+	Description      string            `json:"description,omitempty" yaml:"description,omitempty"`
+	Name             string            `json:"name,omitempty" yaml:"name,omitempty"`
+	In               string            `json:"in,omitempty" yaml:"in,omitempty"`
+	Flow             string            `json:"flow,omitempty" yaml:"flow,omitempty"`
+	AuthorizationURL string            `json:"authorizationUrl,omitempty" yaml:"authorizationUrl,omitempty"`
+	TokenURL         string            `json:"tokenUrl,omitempty" yaml:"tokenUrl,omitempty"`
+	Scopes           map[string]string `json:"scopes,omitempty" yaml:"scopes,omitempty"`
+	Extensions       Extensions        `json:"-" yaml:"-"`
+}
+
+// 以下为 Swagger 2.0 类型的序列化方法：导入时收集 x- 扩展字段，导出时原样写回。
+// 导入统一经由 YAML 解码（兼容 JSON），导出只输出 JSON
+
+type swagger2DocFields swagger2Doc
+
+func (d swagger2Doc) MarshalJSON() ([]byte, error) {
+	return marshalJSONExtensions(swagger2DocFields(d), d.Extensions)
+}
+
+func (d *swagger2Doc) UnmarshalYAML(value *yaml.Node) error {
+	return unmarshalYAMLExtensions(value, (*swagger2DocFields)(d), &d.Extensions)
+}
+
+type swagger2PathItemFields swagger2PathItem
+
+func (p swagger2PathItem) MarshalJSON() ([]byte, error) {
+	return marshalJSONExtensions(swagger2PathItemFields(p), p.Extensions)
+}
+
+func (p *swagger2PathItem) UnmarshalYAML(value *yaml.Node) error {
+	return unmarshalYAMLExtensions(value, (*swagger2PathItemFields)(p), &p.Extensions)
+}
+
+type swagger2OperationFields swagger2Operation
+
+func (o swagger2Operation) MarshalJSON() ([]byte, error) {
+	return marshalJSONExtensions(swagger2OperationFields(o), o.Extensions)
+}
+
+func (o *swagger2Operation) UnmarshalYAML(value *yaml.Node) error {
+	return unmarshalYAMLExtensions(value, (*swagger2OperationFields)(o), &o.Extensions)
+}
+
+type swagger2ParameterFields swagger2Parameter
+
+func (p swagger2Parameter) MarshalJSON() ([]byte, error) {
+	return marshalJSONExtensions(swagger2ParameterFields(p), p.Extensions)
+}
+
+func (p *swagger2Parameter) UnmarshalYAML(value *yaml.Node) error {
+	return unmarshalYAMLExtensions(value, (*swagger2ParameterFields)(p), &p.Extensions)
+}
+
+type swagger2ResponseFields swagger2Response
+
+func (r swagger2Response) MarshalJSON() ([]byte, error) {
+	return marshalJSONExtensions(swagger2ResponseFields(r), r.Extensions)
+}
+
+func (r *swagger2Response) UnmarshalYAML(value *yaml.Node) error {
+	return unmarshalYAMLExtensions(value, (*swagger2ResponseFields)(r), &r.Extensions)
+}
+
+type swagger2HeaderFields swagger2Header
+
+func (h swagger2Header) MarshalJSON() ([]byte, error) {
+	return marshalJSONExtensions(swagger2HeaderFields(h), h.Extensions)
+}
+
+func (h *swagger2Header) UnmarshalYAML(value *yaml.Node) error {
+	return unmarshalYAMLExtensions(value, (*swagger2HeaderFields)(h), &h.Extensions)
+}
+
+type swagger2SecuritySchemeFields swagger2SecurityScheme
+
+func (s swagger2SecurityScheme) MarshalJSON() ([]byte, error) {
+	return marshalJSONExtensions(swagger2SecuritySchemeFields(s), s.Extensions)
+}
+
+func (s *swagger2SecurityScheme) UnmarshalYAML(value *yaml.Node) error {
+	return unmarshalYAMLExtensions(value, (*swagger2SecuritySchemeFields)(s), &s.Extensions)
+}
+
+// FromSwagger2 解析 Swagger 2.0 文档（JSON 或 YAML）并转换为 OpenAPI3，
+// 转换后的文档可以直接赋给 Config.OpenAPI 由 Handler 提供服务
+func FromSwagger2(data []byte) (*OpenAPI3, error) {
+	var doc swagger2Doc
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse swagger 2.0 document: %v", err)
+	}
+	if !strings.HasPrefix(doc.Swagger, "2.") {
+		return nil, fmt.Errorf("unsupported swagger version %q, expected 2.0", doc.Swagger)
+	}
+
+	// 按源文件顺序记录 paths 与 definitions，转换后的文档与 OpenAPI 3 文档一样保留声明顺序
+	var pathOrder, definitionOrder []string
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err == nil && len(root.Content) > 0 {
+		pathOrder = yamlMemberKeys(root.Content[0], "paths")
+		definitionOrder = yamlMemberKeys(root.Content[0], "definitions")
+	}
+
+	c := &swagger2Importer{doc: &doc}
+	result := &OpenAPI3{
+		OpenAPI:      OpenAPI30Version,
+		Info:         doc.Info,
+		Paths:        make(map[string]PathItem, len(doc.Paths)),
+		Tags:         doc.Tags,
+		Servers:      c.servers(),
+		Security:     doc.Security,
+		ExternalDocs: doc.ExternalDocs,
+		Extensions:   vendorExtensions(doc.Extensions),
+	}
+
+	// definitions -> components.schemas
+	for _, name := range orderedKeys(doc.Definitions, definitionOrder) {
+		result.Components.SetSchema(name, doc.Definitions[name])
+	}
+
+	// 全局非 body/formData 参数 -> components.parameters，body/formData 参数在引用处展开为 requestBody
+	for name, param := range doc.Parameters {
+		if param.In == ParamInBody || param.In == ParamInFormData {
+			continue
+		}
+		if result.Components.Parameters == nil {
+			result.Components.Parameters = make(map[string]Parameter)
+		}
+		result.Components.Parameters[name] = c.parameter(param)
+	}
+
+	// 全局响应 -> components.responses
+	for name, resp := range doc.Responses {
+		if result.Components.Responses == nil {
+			result.Components.Responses = make(map[string]Response)
+		}
+		result.Components.Responses[name] = c.response(resp, doc.Produces)
+	}
+
+	// securityDefinitions -> components.securitySchemes
+	for name, scheme := range doc.SecurityDefinitions {
+		if result.Components.SecuritySchemes == nil {
+			result.Components.SecuritySchemes = make(map[string]SecurityScheme)
+		}
+		result.Components.SecuritySchemes[name] = convertSwagger2SecurityScheme(scheme)
+	}
+
+	for _, path := range orderedKeys(doc.Paths, pathOrder) {
+		item := doc.Paths[path]
+		pathItem := PathItem{Ref: item.Ref, Extensions: vendorExtensions(item.Extensions)}
+		var pathBodyParams []swagger2Parameter
+		for _, param := range c.resolveParameters(item.Parameters) {
+			if param.In == ParamInBody || param.In == ParamInFormData {
+				pathBodyParams = append(pathBodyParams, param)
+				continue
+			}
+			pathItem.Parameters = append(pathItem.Parameters, c.parameter(param))
+		}
+		pathItem.Get = c.operation(item.Get, pathBodyParams)
+		pathItem.Put = c.operation(item.Put, pathBodyParams)
+		pathItem.Post = c.operation(item.Post, pathBodyParams)
+		pathItem.Delete = c.operation(item.Delete, pathBodyParams)
+		pathItem.Patch = c.operation(item.Patch, pathBodyParams)
+		result.SetPath(path, pathItem)
+	}
+
+	// 将 #/definitions/ 引用改写为 #/components/schemas/
+	result = rewriteDocumentSchemas(result, func(pointer string, schema *Schema) *Schema {
+		schema.Ref = rewriteRefPrefix(schema.Ref, swagger2DefinitionsPrefix, componentsSchemasPrefix)
+		if schema.Discriminator != nil {
+			for value, ref := range schema.Discriminator.Mapping {
+				schema.Discriminator.Mapping[value] = rewriteRefPrefix(ref, swagger2DefinitionsPrefix, componentsSchemasPrefix)
+			}
+		}
+		return schema
+	})
+
+	return result, nil
+}
+
+// swagger2Importer 保存 Swagger 2.0 -> OpenAPI3 转换过程中的上下文
+type swagger2Importer struct {
+	doc *swagger2Doc
+}
+
+// servers 将 host/basePath/schemes 转换为 servers 列表
+func (c *swagger2Importer) servers() []Server {
+	basePath := c.doc.BasePath
+	if basePath == "" {
+		basePath = "/"
+	}
+	if c.doc.Host == "" {
+		return []Server{{URL: basePath}}
+	}
+	if len(c.doc.Schemes) == 0 {
+		// 未声明 schemes 时沿用访问文档所使用的协议
+		return []Server{{URL: "//" + c.doc.Host + strings.TrimSuffix(basePath, "/")}}
+	}
+	servers := make([]Server, 0, len(c.doc.Schemes))
+	for _, scheme := range c.doc.Schemes {
+		servers = append(servers, Server{URL: scheme + "://" + c.doc.Host + strings.TrimSuffix(basePath, "/")})
+	}
+	return servers
+}
+
+// resolveParameters 展开 #/parameters/ 引用
+func (c *swagger2Importer) resolveParameters(params []swagger2Parameter) []swagger2Parameter {
+	result := make([]swagger2Parameter, 0, len(params))
+	for _, param := range params {
+		if param.Ref != "" {
+			resolved, exists := c.doc.Parameters[strings.TrimPrefix(param.Ref, swagger2ParametersPrefix)]
+			if !exists {
+				continue
+			}
+			param = resolved
+		}
+		result = append(result, param)
+	}
+	return result
+}
+
+// operation 转换单个操作，body 与 formData 参数合并为 requestBody
+func (c *swagger2Importer) operation(op *swagger2Operation, pathBodyParams []swagger2Parameter) *Operation {
+	if op == nil {
+		return nil
+	}
+
+	consumes := op.Consumes
+	if len(consumes) == 0 {
+		consumes = c.doc.Consumes
+	}
+	produces := op.Produces
+	if len(produces) == 0 {
+		produces = c.doc.Produces
+	}
+
+	result := &Operation{
+		Tags:        op.Tags,
+		Summary:     op.Summary,
+		Description: op.Description,
+		OperationID: op.OperationID,
+		Deprecated:  op.Deprecated,
+		Security:    op.Security,
+		Responses:   make(map[string]Response, len(op.Responses)),
+		Extensions:  vendorExtensions(op.Extensions),
+	}
+
+	var bodyParam *swagger2Parameter
+	var formParams []swagger2Parameter
+	params := append(append([]swagger2Parameter(nil), pathBodyParams...), c.resolveParameters(op.Parameters)...)
+	for i := range params {
+		switch params[i].In {
+		case ParamInBody:
+			bodyParam = &params[i]
+		case ParamInFormData:
+			formParams = append(formParams, params[i])
+		default:
+			result.Parameters = append(result.Parameters, c.parameter(params[i]))
+		}
+	}
+
+	if bodyParam != nil {
+		if len(consumes) == 0 {
+			consumes = []string{MIMEApplicationJSON}
+		}
+		content := make(map[string]MediaType, len(consumes))
+		for _, mediaType := range consumes {
+			content[mediaType] = MediaType{Schema: bodyParam.Schema}
+		}
+		result.RequestBody = &RequestBody{
+			Description: bodyParam.Description,
+			Content:     content,
+			Required:    bodyParam.Required,
+			Extensions:  vendorExtensions(bodyParam.Extensions),
+		}
+	} else if len(formParams) > 0 {
+		result.RequestBody = formRequestBody(formParams, consumes)
+	}
+
+	for code, resp := range op.Responses {
+		if resp.Ref != "" {
+			if resolved, exists := c.doc.Responses[strings.TrimPrefix(resp.Ref, swagger2ResponsesPrefix)]; exists {
+				resp = resolved
+			}
+		}
+		result.Responses[code] = c.response(resp, produces)
+	}
+
+	return result
+}
+
+// formRequestBody 将 formData 参数合并为 multipart/form-data 或 application/x-www-form-urlencoded 请求体
+func formRequestBody(params []swagger2Parameter, consumes []string) *RequestBody {
+	schema := &Schema{
+		Type:       SchemaType{"object"},
+		Properties: make(map[string]*Schema, len(params)),
+	}
+	hasFile := false
+	for _, param := range params {
+		prop := param.swagger2Items.toSchema()
+		prop.Description = param.Description
+		prop.Extensions = vendorExtensions(param.Extensions)
+		if param.Type == ParamTypeFile {
+			hasFile = true
+		}
+		schema.Properties[param.Name] = prop
+		if param.Required {
+			schema.Required = append(schema.Required, param.Name)
+		}
+	}
+
+	content := make(map[string]MediaType)
+	for _, mediaType := range consumes {
+		if isFormContentType(mediaType) {
+			content[mediaType] = MediaType{Schema: schema}
+		}
+	}
+	if len(content) == 0 {
+		if hasFile {
+			content[MIMEMultipartFormData] = MediaType{Schema: schema}
+		} else {
+			content[MIMEFormURLEncoded] = MediaType{Schema: schema}
+		}
+	}
+
+	return &RequestBody{
+		Content:  content,
+		Required: len(schema.Required) > 0,
+	}
+}
+
+// parameter 转换 query/header/path 参数
+func (c *swagger2Importer) parameter(param swagger2Parameter) Parameter {
+	result := Parameter{
+		Name:            param.Name,
+		In:              param.In,
+		Description:     param.Description,
+		Required:        param.Required,
+		AllowEmptyValue: param.AllowEmptyValue,
+		Schema:          param.swagger2Items.toSchema(),
+		Extensions:      vendorExtensions(param.Extensions),
+	}
+
+	// collectionFormat -> style/explode
+	switch param.CollectionFormat {
+	case "csv":
+		if param.In == "query" {
+			result.Style = "form"
+		} else {
+			result.Style = "simple"
+		}
+	case "multi":
+		result.Style = "form"
+		result.Explode = true
+	case "ssv":
+		result.Style = "spaceDelimited"
+	case "pipes":
+		result.Style = "pipeDelimited"
+	}
+	return result
+}
+
+// response 转换响应，schema 按 produces 展开为 content
+func (c *swagger2Importer) response(resp swagger2Response, produces []string) Response {
+	result := Response{Description: resp.Description, Extensions: vendorExtensions(resp.Extensions)}
+
+	if resp.Schema != nil || len(resp.Examples) > 0 {
+		if len(produces) == 0 {
+			produces = []string{MIMEApplicationJSON}
+		}
+		result.Content = make(map[string]MediaType, len(produces))
+		for _, mediaType := range produces {
+			result.Content[mediaType] = MediaType{Schema: resp.Schema, Example: resp.Examples[mediaType]}
+		}
+		for mediaType, example := range resp.Examples {
+			if _, exists := result.Content[mediaType]; !exists {
+				result.Content[mediaType] = MediaType{Schema: resp.Schema, Example: example}
+			}
+		}
+	}
+
+	if len(resp.Headers) > 0 {
+		result.Headers = make(map[string]Header, len(resp.Headers))
+		for name, header := range resp.Headers {
+			result.Headers[name] = Header{
+				Description: header.Description,
+				Schema:      header.swagger2Items.toSchema(),
+				Extensions:  vendorExtensions(header.Extensions),
+			}
+		}
+	}
+	return result
+}
+
+// toSchema 将 Swagger 2.0 简化 Schema 转换为 OpenAPI3 Schema，type: file 转换为 string/binary
+func (i *swagger2Items) toSchema() *Schema {
+	if i == nil {
+		return nil
+	}
+	schema := &Schema{
+		Format:      i.Format,
+		Default:     i.Default,
+		Maximum:     i.Maximum,
+		Minimum:     i.Minimum,
+		MaxLength:   i.MaxLength,
+		MinLength:   i.MinLength,
+		Pattern:     i.Pattern,
+		MaxItems:    i.MaxItems,
+		MinItems:    i.MinItems,
+		UniqueItems: i.UniqueItems,
+		Enum:        i.Enum,
+		MultipleOf:  i.MultipleOf,
+		Items:       i.Items.toSchema(),
+	}
+	if i.Type != "" {
+		schema.Type = SchemaType{i.Type}
+	}
+	if i.Type == ParamTypeFile {
+		schema.Type = SchemaType{ParamTypeString}
+		schema.Format = ParamFormatBinary
+	}
+	if i.ExclusiveMaximum {
+		schema.ExclusiveMaximum = &BoolOrNumber{Bool: true}
+	}
+	if i.ExclusiveMinimum {
+		schema.ExclusiveMinimum = &BoolOrNumber{Bool: true}
+	}
+	return schema
+}
+
+// convertSwagger2SecurityScheme 将 securityDefinitions 条目转换为 OpenAPI3 SecurityScheme
+func convertSwagger2SecurityScheme(scheme swagger2SecurityScheme) SecurityScheme {
+	result := SecurityScheme{
+		Type:        scheme.Type,
+		Description: scheme.Description,
+		Name:        scheme.Name,
+		In:          scheme.In,
+		Extensions:  vendorExtensions(scheme.Extensions),
+	}
+	switch scheme.Type {
+	case "basic":
+		result.Type = "http"
+		result.Scheme = "basic"
+	case "oauth2":
+		flow := &OAuthFlow{
+			AuthorizationURL: scheme.AuthorizationURL,
+			TokenURL:         scheme.TokenURL,
+			Scopes:           scheme.Scopes,
+		}
+		if flow.Scopes == nil {
+			flow.Scopes = map[string]string{}
+		}
+		result.Flows = &OAuthFlows{}
+		switch scheme.Flow {
+		case "implicit":
+			result.Flows.Implicit = flow
+		case "password":
+			result.Flows.Password = flow
+		case "application":
+			result.Flows.ClientCredentials = flow
+		case "accessCode":
+			result.Flows.AuthorizationCode = flow
+		}
+	}
+	return result
+}
+
+// rewriteRefPrefix 将 $ref 的前缀从 from 替换为 to，其他引用保持不变
+func rewriteRefPrefix(ref, from, to string) string {
+	if !strings.HasPrefix(ref, from) {
+		return ref
+	}
+	return to + strings.TrimPrefix(ref, from)
+}
+
+// vendorExtensions 返回 ext 中 x- 前缀的扩展字段，没有时返回 nil
+func vendorExtensions(ext Extensions) Extensions {
+	var result Extensions
+	for key, value := range ext {
+		if strings.HasPrefix(key, "x-") {
+			result.Set(key, value)
+		}
+	}
+	return result
+}
+
+// ToSwagger2 将 OpenAPI3 文档导出为 Swagger 2.0 JSON，无法在 2.0 中表达的内容会被丢弃，
+// 需要获取这些内容时请使用 ExportSwagger2
+func ToSwagger2(doc *OpenAPI3) ([]byte, error) {
+	data, _, err := ExportSwagger2(doc)
+	return data, err
+}
+
+// ExportSwagger2 将 OpenAPI3 文档导出为 Swagger 2.0 JSON，并返回无法在 2.0 中表达的内容
+// （如 oneOf/anyOf、多个 servers、cookie 参数）。OpenAPI 3.1 文档会先降级为 3.0。
+func ExportSwagger2(doc *OpenAPI3) ([]byte, []DowngradeWarning, error) {
+	if doc == nil {
+		return nil, nil, fmt.Errorf("openapi document is nil")
+	}
+
+	doc, warnings := DowngradeToOpenAPI30(doc)
+	e := &swagger2Exporter{doc: doc, warnings: warnings}
+	result := e.export()
+
+	sort.SliceStable(e.warnings, func(i, j int) bool {
+		return e.warnings[i].Pointer < e.warnings[j].Pointer
+	})
+
+	// discriminator 在 2.0 中仅为属性名字符串，需要在通用结构上改写
+	var tree map[string]any
+	raw, err := json.Marshal(result)
+	if err != nil {
+		return nil, e.warnings, fmt.Errorf("failed to encode swagger 2.0 document: %v", err)
+	}
+	if err := json.Unmarshal(raw, &tree); err != nil {
+		return nil, e.warnings, fmt.Errorf("failed to encode swagger 2.0 document: %v", err)
+	}
+	flattenSwagger2Discriminators(tree)
+
+	data, err := json.MarshalIndent(tree, "", "  ")
+	if err != nil {
+		return nil, e.warnings, fmt.Errorf("failed to encode swagger 2.0 document: %v", err)
+	}
+	return data, e.warnings, nil
+}
+
+// swagger2Exporter 保存 OpenAPI3 -> Swagger 2.0 转换过程中的上下文
+type swagger2Exporter struct {
+	doc      *OpenAPI3
+	warnings []DowngradeWarning
+}
+
+func (e *swagger2Exporter) warn(pointer, format string, args ...any) {
+	e.warnings = append(e.warnings, DowngradeWarning{Pointer: pointer, Message: fmt.Sprintf(format, args...)})
+}
+
+func (e *swagger2Exporter) export() *swagger2Doc {
+	// 先清理 Schema 中 2.0 不支持的关键字并改写 $ref
+	doc := rewriteDocumentSchemas(e.doc, e.schema)
+
+	// info.name 不是规范字段，2.0 的 info 只保留规范字段与扩展字段
+	info := doc.Info
+	info.Name = ""
+	result := &swagger2Doc{
+		Swagger:      "2.0",
+		Info:         info,
+		Paths:        make(map[string]swagger2PathItem, len(doc.Paths)),
+		Security:     doc.Security,
+		Tags:         doc.Tags,
+		ExternalDocs: doc.ExternalDocs,
+		Extensions:   vendorExtensions(doc.Extensions),
+	}
+	e.servers(doc.Servers, result)
+
+	if len(doc.Components.Schemas) > 0 {
+		result.Definitions = doc.Components.Schemas
+	}
+	for _, name := range sortedKeys(doc.Components.Parameters) {
+		param, ok := e.parameter("#/components/parameters/"+escapePointer(name), doc.Components.Parameters[name])
+		if !ok {
+			continue
+		}
+		if result.Parameters == nil {
+			result.Parameters = make(map[string]swagger2Parameter)
+		}
+		result.Parameters[name] = param
+	}
+	for _, name := range sortedKeys(doc.Components.Responses) {
+		if result.Responses == nil {
+			result.Responses = make(map[string]swagger2Response)
+		}
+		resp, _ := e.response("#/components/responses/"+escapePointer(name), doc.Components.Responses[name], nil)
+		result.Responses[name] = resp
+	}
+	for _, name := range sortedKeys(doc.Components.SecuritySchemes) {
+		e.securityScheme(name, doc.Components.SecuritySchemes[name], result)
+	}
+	if len(doc.Components.RequestBodies) > 0 {
+		e.warn("#/components/requestBodies", "reusable request bodies are not supported by Swagger 2.0 and were dropped")
+	}
+	if len(doc.Components.Headers) > 0 {
+		e.warn("#/components/headers", "reusable headers are not supported by Swagger 2.0 and were dropped")
+	}
+	if len(doc.Components.Links) > 0 {
+		e.warn("#/components/links", "links are not supported by Swagger 2.0 and were dropped")
+	}
+
+	var allConsumes, allProduces []string
+	for _, path := range sortedKeys(doc.Paths) {
+		item := doc.Paths[path]
+		pointer := "#/paths/" + escapePointer(path)
+		pathItem := swagger2PathItem{Ref: item.Ref, Extensions: vendorExtensions(item.Extensions)}
+		for i, param := range item.Parameters {
+			if p, ok := e.parameter(pointer+"/parameters/"+strconv.Itoa(i), param); ok {
+				pathItem.Parameters = append(pathItem.Parameters, p)
+			}
+		}
+		for _, op := range item.operations() {
+			converted, consumes, produces := e.operation(pointer+"/"+op.method, op.operation)
+			allConsumes = appendUnique(allConsumes, consumes...)
+			allProduces = appendUnique(allProduces, produces...)
+			switch op.method {
+			case "get":
+				pathItem.Get = converted
+			case "put":
+				pathItem.Put = converted
+			case "post":
+				pathItem.Post = converted
+			case "delete":
+				pathItem.Delete = converted
+			case "patch":
+				pathItem.Patch = converted
+			}
+		}
+		result.Paths[path] = pathItem
+	}
+
+	// 所有操作的媒体类型一致时提升为全局 consumes/produces
+	result.Consumes, result.Produces = allConsumes, allProduces
+	for _, item := range result.Paths {
+		for _, op := range []*swagger2Operation{item.Get, item.Put, item.Post, item.Delete, item.Patch} {
+			if op == nil {
+				continue
+			}
+			if len(op.Consumes) > 0 && (len(allConsumes) != 1 || op.Consumes[0] != allConsumes[0]) {
+				result.Consumes = nil
+			}
+			if len(op.Produces) > 0 && (len(allProduces) != 1 || op.Produces[0] != allProduces[0]) {
+				result.Produces = nil
+			}
+		}
+	}
+	for _, item := range result.Paths {
+		for _, op := range []*swagger2Operation{item.Get, item.Put, item.Post, item.Delete, item.Patch} {
+			if op == nil {
+				continue
+			}
+			if result.Consumes != nil {
+				op.Consumes = nil
+			}
+			if result.Produces != nil {
+				op.Produces = nil
+			}
+		}
+	}
+
+	if len(doc.Webhooks) > 0 {
+		e.warn("#/webhooks", "webhooks are not supported by Swagger 2.0 and were dropped")
+	}
+	return result
+}
+
+// servers 将第一个 server 转换为 host/basePath/schemes，仅 scheme 不同的 servers 合并为多个 schemes
+func (e *swagger2Exporter) servers(servers []Server, result *swagger2Doc) {
+	if len(servers) == 0 {
+		return
+	}
+	for i, server := range servers {
+		serverURL := server.URL
+		for name, variable := range server.Variables {
+			serverURL = strings.ReplaceAll(serverURL, "{"+name+"}", variable.Default)
+		}
+		if len(server.Variables) > 0 {
+			e.warn("#/servers/"+strconv.Itoa(i)+"/variables", "server variables were replaced by their default values")
+		}
+
+		u, err := url.Parse(serverURL)
+		if err != nil {
+			e.warn("#/servers/"+strconv.Itoa(i), "invalid server url %q was dropped", server.URL)
+			continue
+		}
+		basePath := u.Path
+		if basePath == "" {
+			basePath = "/"
+		}
+		if i == 0 || result.Host == "" && result.BasePath == "" {
+			result.Host = u.Host
+			result.BasePath = basePath
+		} else if u.Host != result.Host || basePath != result.BasePath {
+			e.warn("#/servers/"+strconv.Itoa(i), "only one host/basePath can be expressed in Swagger 2.0, server %q was dropped", server.URL)
+			continue
+		}
+		if u.Scheme != "" {
+			result.Schemes = appendUnique(result.Schemes, u.Scheme)
+		}
+	}
+}
+
+// operation 转换单个操作，返回操作本身及其 consumes/produces
+func (e *swagger2Exporter) operation(pointer string, op *Operation) (*swagger2Operation, []string, []string) {
+	result := &swagger2Operation{
+		Tags:        op.Tags,
+		Summary:     op.Summary,
+		Description: op.Description,
+		OperationID: op.OperationID,
+		Deprecated:  op.Deprecated,
+		Security:    op.Security,
+		Responses:   make(map[string]swagger2Response, len(op.Responses)),
+		Extensions:  vendorExtensions(op.Extensions),
+	}
+
+	for i, param := range op.Parameters {
+		if p, ok := e.parameter(pointer+"/parameters/"+strconv.Itoa(i), param); ok {
+			result.Parameters = append(result.Parameters, p)
+		}
+	}
+
+	// 与 /v3/api-docs 相同，@consumes/@produces 给出的媒体类型优先，其余保持源文件顺序
+	parser := operationAnnotations(op)
+	if op.RequestBody != nil {
+		body := *op.RequestBody
+		body.Content, body.contentOrder = preferredContent(body.Content, body.OrderedContentTypes(), requestContentTypes(parser))
+		params, consumes := e.requestBody(pointer+"/requestBody", &body)
+		result.Parameters = append(result.Parameters, params...)
+		result.Consumes = consumes
+	}
+
+	for _, code := range sortedKeys(op.Responses) {
+		resp, produces := e.response(pointer+"/responses/"+escapePointer(code), op.Responses[code], successProduces(code, parser.GetArray(TagProduces)))
+		result.Responses[code] = resp
+		result.Produces = appendUnique(result.Produces, produces...)
+	}
+
+	if len(op.Servers) > 0 {
+		e.warn(pointer+"/servers", "operation level servers are not supported by Swagger 2.0 and were dropped")
+	}
+	if len(op.Callbacks) > 0 {
+		e.warn(pointer+"/callbacks", "callbacks are not supported by Swagger 2.0 and were dropped")
+	}
+	return result, result.Consumes, result.Produces
+}
+
+// requestBody 将 requestBody 转换为 body 参数，或在表单类型时展开为 formData 参数
+func (e *swagger2Exporter) requestBody(pointer string, body *RequestBody) ([]swagger2Parameter, []string) {
+	var formTypes, otherTypes []string
+	for _, mediaType := range body.OrderedContentTypes() {
+		if isFormContentType(mediaType) {
+			formTypes = append(formTypes, mediaType)
+		} else {
+			otherTypes = append(otherTypes, mediaType)
+		}
+	}
+
+	if len(otherTypes) > 0 {
+		if len(formTypes) > 0 {
+			e.warn(pointer+"/content", "form content types %v cannot be combined with a body parameter and were dropped", formTypes)
+		}
+		schema := body.Content[otherTypes[0]].Schema
+		for _, mediaType := range otherTypes[1:] {
+			if !reflect.DeepEqual(body.Content[mediaType].Schema, schema) {
+				e.warn(pointer+"/content/"+escapePointer(mediaType), "Swagger 2.0 allows one body schema, schema of %q was dropped", mediaType)
+			}
+		}
+		if schema == nil {
+			schema = &Schema{}
+		}
+		return []swagger2Parameter{{
+			Name:        ParamInBody,
+			In:          ParamInBody,
+			Description: body.Description,
+			Required:    body.Required,
+			Schema:      schema,
+			Extensions:  vendorExtensions(body.Extensions),
+		}}, otherTypes
+	}
+	if len(formTypes) == 0 {
+		return nil, nil
+	}
+
+	// 表单请求体展开为 formData 参数
+	contentPointer := pointer + "/content/" + escapePointer(formTypes[0]) + "/schema"
+	schema := resolveSchema(body.Content[formTypes[0]].Schema, e.doc.Components.Schemas)
+	if schema == nil || len(schema.Properties) == 0 {
+		return nil, formTypes
+	}
+	required := stringSet(schema.Required)
+	params := make([]swagger2Parameter, 0, len(schema.Properties))
+	for _, name := range sortedKeys(schema.Properties) {
+		prop := resolveSchema(schema.Properties[name], e.doc.Components.Schemas)
+		propPointer := contentPointer + "/properties/" + escapePointer(name)
+		items := e.items(propPointer, prop)
+		if isBinarySchema(prop) {
+			items = swagger2Items{Type: ParamTypeFile}
+		} else if prop.Type.Is("array") && isBinarySchema(prop.Items) {
+			e.warn(propPointer, "arrays of files are not supported by Swagger 2.0, emitted as a single file")
+			items = swagger2Items{Type: ParamTypeFile}
+		}
+		params = append(params, swagger2Parameter{
+			Name:          name,
+			In:            ParamInFormData,
+			Description:   prop.Description,
+			Required:      required[name],
+			swagger2Items: items,
+			Extensions:    vendorExtensions(prop.Extensions),
+		})
+	}
+	return params, formTypes
+}
+
+// parameter 转换 query/header/path 参数，cookie 参数无法表达时返回 false
+func (e *swagger2Exporter) parameter(pointer string, param Parameter) (swagger2Parameter, bool) {
+	if param.In == "cookie" {
+		e.warn(pointer, "cookie parameter %q is not supported by Swagger 2.0 and was dropped", param.Name)
+		return swagger2Parameter{}, false
+	}
+	if len(param.Content) > 0 {
+		e.warn(pointer+"/content", "parameter content is not supported by Swagger 2.0, only the schema was kept")
+	}
+
+	schema := resolveSchema(param.Schema, e.doc.Components.Schemas)
+	result := swagger2Parameter{
+		Name:            param.Name,
+		In:              param.In,
+		Description:     param.Description,
+		Required:        param.Required,
+		AllowEmptyValue: param.AllowEmptyValue,
+		swagger2Items:   e.items(pointer+"/schema", schema),
+		Extensions:      vendorExtensions(param.Extensions),
+	}
+
+	// style/explode -> collectionFormat
+	if result.Type == "array" {
+		switch param.Style {
+		case "spaceDelimited":
+			result.CollectionFormat = "ssv"
+		case "pipeDelimited":
+			result.CollectionFormat = "pipes"
+		case "form", "":
+			if param.In == "query" && (param.Explode || param.Style == "") {
+				result.CollectionFormat = "multi"
+			} else {
+				result.CollectionFormat = "csv"
+			}
+		default:
+			result.CollectionFormat = "csv"
+		}
+	}
+	return result, true
+}
+
+// response 转换响应，按 produces 调整后的首个媒体类型选取 schema，返回响应及其 produces
+func (e *swagger2Exporter) response(pointer string, resp Response, produces []string) (swagger2Response, []string) {
+	result := swagger2Response{Description: resp.Description, Extensions: vendorExtensions(resp.Extensions)}
+	content, mediaTypes := responseContent(&resp, produces)
+	resp.Content = content
+	if len(mediaTypes) > 0 {
+		result.Schema = resp.Content[mediaTypes[0]].Schema
+		for _, mediaType := range mediaTypes {
+			media := resp.Content[mediaType]
+			if mediaType != mediaTypes[0] && !reflect.DeepEqual(media.Schema, result.Schema) {
+				e.warn(pointer+"/content/"+escapePointer(mediaType), "Swagger 2.0 allows one response schema, schema of %q was dropped", mediaType)
+			}
+			if media.Example != nil {
+				if result.Examples == nil {
+					result.Examples = make(map[string]interface{})
+				}
+				result.Examples[mediaType] = media.Example
+			}
+		}
+	}
+	for _, name := range sortedKeys(resp.Headers) {
+		header := resp.Headers[name]
+		if result.Headers == nil {
+			result.Headers = make(map[string]swagger2Header)
+		}
+		schema := resolveSchema(header.Schema, e.doc.Components.Schemas)
+		result.Headers[name] = swagger2Header{
+			Description:   header.Description,
+			swagger2Items: e.items(pointer+"/headers/"+escapePointer(name)+"/schema", schema),
+			Extensions:    vendorExtensions(header.Extensions),
+		}
+	}
+	if len(resp.Links) > 0 {
+		e.warn(pointer+"/links", "links are not supported by Swagger 2.0 and were dropped")
+	}
+	return result, mediaTypes
+}
+
+// items 将非 body 位置使用的 Schema 转换为 Swagger 2.0 简化 Schema
+func (e *swagger2Exporter) items(pointer string, schema *Schema) swagger2Items {
+	if schema == nil {
+		return swagger2Items{Type: ParamTypeString}
+	}
+	result := swagger2Items{
+		Type:        schema.Type.String(),
+		Format:      schema.Format,
+		Default:     schema.Default,
+		Maximum:     schema.Maximum,
+		Minimum:     schema.Minimum,
+		MaxLength:   schema.MaxLength,
+		MinLength:   schema.MinLength,
+		Pattern:     schema.Pattern,
+		MaxItems:    schema.MaxItems,
+		MinItems:    schema.MinItems,
+		UniqueItems: schema.UniqueItems,
+		Enum:        schema.Enum,
+		MultipleOf:  schema.MultipleOf,
+	}
+	if schema.ExclusiveMaximum != nil {
+		result.ExclusiveMaximum = schema.ExclusiveMaximum.Bool
+	}
+	if schema.ExclusiveMinimum != nil {
+		result.ExclusiveMinimum = schema.ExclusiveMinimum.Bool
+	}
+	switch {
+	case schema.Type.Is("object") || schema.Ref != "" || len(schema.Properties) > 0:
+		e.warn(pointer, "object schemas are only allowed in body parameters in Swagger 2.0, emitted as string")
+		result = swagger2Items{Type: ParamTypeString}
+	case schema.Type.Is("array"):
+		items := e.items(pointer+"/items", resolveSchema(schema.Items, e.doc.Components.Schemas))
+		result.Items = &items
+	case result.Type == "":
+		result.Type = ParamTypeString
+	}
+	return result
+}
+
+// schema 清理 2.0 不支持的 Schema 关键字，并将 $ref 改写为 #/definitions/
+func (e *swagger2Exporter) schema(pointer string, schema *Schema) *Schema {
+	schema.Ref = rewriteRefPrefix(schema.Ref, componentsSchemasPrefix, swagger2DefinitionsPrefix)
+
+	if len(schema.OneOf) > 0 {
+		e.warn(pointer+"/oneOf", "oneOf is not supported by Swagger 2.0 and was dropped")
+		schema.OneOf = nil
+	}
+	if len(schema.AnyOf) > 0 {
+		e.warn(pointer+"/anyOf", "anyOf is not supported by Swagger 2.0 and was dropped")
+		schema.AnyOf = nil
+	}
+	if schema.Not != nil {
+		e.warn(pointer+"/not", "not is not supported by Swagger 2.0 and was dropped")
+		schema.Not = nil
+	}
+	if schema.Nullable {
+		e.warn(pointer+"/nullable", "nullable is not supported by Swagger 2.0 and was dropped")
+		schema.Nullable = false
+	}
+	if schema.WriteOnly {
+		e.warn(pointer+"/writeOnly", "writeOnly is not supported by Swagger 2.0 and was dropped")
+		schema.WriteOnly = false
+	}
+	if schema.Deprecated {
+		e.warn(pointer+"/deprecated", "schema deprecation is not supported by Swagger 2.0 and was dropped")
+		schema.Deprecated = false
+	}
+	if schema.Discriminator != nil && len(schema.Discriminator.Mapping) > 0 {
+		e.warn(pointer+"/discriminator/mapping", "discriminator mapping is not supported by Swagger 2.0 and was dropped")
+		schema.Discriminator.Mapping = nil
+	}
+	return schema
+}
+
+// securityScheme 转换安全方案，OAuth2 的多个 flow 拆分为多个 securityDefinitions
+func (e *swagger2Exporter) securityScheme(name string, scheme SecurityScheme, result *swagger2Doc) {
+	pointer := "#/components/securitySchemes/" + escapePointer(name)
+	add := func(key string, def swagger2SecurityScheme) {
+		if result.SecurityDefinitions == nil {
+			result.SecurityDefinitions = make(map[string]swagger2SecurityScheme)
+		}
+		def.Extensions = vendorExtensions(scheme.Extensions)
+		result.SecurityDefinitions[key] = def
+	}
+
+	switch scheme.Type {
+	case "http":
+		if strings.EqualFold(scheme.Scheme, "basic") {
+			add(name, swagger2SecurityScheme{Type: "basic", Description: scheme.Description})
+			return
+		}
+		e.warn(pointer, "http scheme %q is not supported by Swagger 2.0, emitted as an Authorization header api key", scheme.Scheme)
+		add(name, swagger2SecurityScheme{Type: "apiKey", Description: scheme.Description, Name: "Authorization", In: "header"})
+	case "apiKey":
+		if scheme.In == "cookie" {
+			e.warn(pointer, "cookie api keys are not supported by Swagger 2.0 and were dropped")
+			return
+		}
+		add(name, swagger2SecurityScheme{Type: "apiKey", Description: scheme.Description, Name: scheme.Name, In: scheme.In})
+	case "oauth2":
+		if scheme.Flows == nil {
+			return
+		}
+		flows := []struct {
+			name string
+			flow *OAuthFlow
+		}{
+			{"implicit", scheme.Flows.Implicit},
+			{"password", scheme.Flows.Password},
+			{"application", scheme.Flows.ClientCredentials},
+			{"accessCode", scheme.Flows.AuthorizationCode},
+		}
+		count := 0
+		for _, f := range flows {
+			if f.flow == nil {
+				continue
+			}
+			key := name
+			if count > 0 {
+				key = name + "_" + f.name
+				e.warn(pointer+"/flows", "Swagger 2.0 allows one flow per security definition, flow %q exported as %q", f.name, key)
+			}
+			count++
+			add(key, swagger2SecurityScheme{
+				Type:             "oauth2",
+				Description:      scheme.Description,
+				Flow:             f.name,
+				AuthorizationURL: f.flow.AuthorizationURL,
+				TokenURL:         f.flow.TokenURL,
+				Scopes:           f.flow.Scopes,
+			})
+		}
+	default:
+		e.warn(pointer, "security scheme type %q is not supported by Swagger 2.0 and was dropped", scheme.Type)
+	}
+}
+
+// isBinarySchema 判断 Schema 是否表示二进制文件
+func isBinarySchema(schema *Schema) bool {
+	return schema != nil && schema.Type.Is(ParamTypeString) && schema.Format == ParamFormatBinary
+}
+
+// flattenSwagger2Discriminators 将通用结构中所有 Schema 的 discriminator 对象改写为属性名字符串
+func flattenSwagger2Discriminators(tree map[string]any) {
+	var walk func(node any)
+	walk = func(node any) {
+		schema, ok := node.(map[string]any)
+		if !ok {
+			return
+		}
+		if d, ok := schema["discriminator"].(map[string]any); ok {
+			schema["discriminator"] = d["propertyName"]
+		}
+		for _, key := range []string{"items", "additionalProperties", "not"} {
+			walk(schema[key])
+		}
+		if props, ok := schema["properties"].(map[string]any); ok {
+			for _, prop := range props {
+				walk(prop)
+			}
+		}
+		if allOf, ok := schema["allOf"].([]any); ok {
+			for _, item := range allOf {
+				walk(item)
+			}
+		}
+	}
+
+	if defs, ok := tree["definitions"].(map[string]any); ok {
+		for _, def := range defs {
+			walk(def)
+		}
+	}
+	if params, ok := tree["parameters"].(map[string]any); ok {
+		for _, param := range params {
+			if p, ok := param.(map[string]any); ok {
+				walk(p["schema"])
+			}
+		}
+	}
+	if responses, ok := tree["responses"].(map[string]any); ok {
+		for _, resp := range responses {
+			if r, ok := resp.(map[string]any); ok {
+				walk(r["schema"])
+			}
+		}
+	}
+	paths, _ := tree["paths"].(map[string]any)
+	for _, item := range paths {
+		pathItem, _ := item.(map[string]any)
+		for _, op := range pathItem {
+			operation, ok := op.(map[string]any)
+			if !ok {
+				continue
+			}
+			if params, ok := operation["parameters"].([]any); ok {
+				for _, param := range params {
+					if p, ok := param.(map[string]any); ok {
+						walk(p["schema"])
+					}
+				}
+			}
+			if responses, ok := operation["responses"].(map[string]any); ok {
+				for _, resp := range responses {
+					if r, ok := resp.(map[string]any); ok {
+						walk(r["schema"])
+					}
+				}
+			}
+		}
+	}
+}
+
+// appendUnique 追加尚不存在的元素并保持原有顺序
+func appendUnique(list []string, values ...string) []string {
+	for _, v := range values {
+		exists := false
+		for _, existing := range list {
+			if existing == v {
+				exists = true
+				break
+			}
+		}
+		if !exists {
+			list = append(list, v)
+		}
+	}
+	return list
+}
@@ -0,0 +1,401 @@
+package knife4g
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// petstoreSwagger2 读取并导入 testdata 中的 Swagger 2.0 示例文档
+func petstoreSwagger2(t *testing.T) *OpenAPI3 {
+	t.Helper()
+	data, err := os.ReadFile("testdata/petstore-swagger2.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err := FromSwagger2(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return doc
+}
+
+func TestFromSwagger2(t *testing.T) {
+	doc := petstoreSwagger2(t)
+
+	if doc.OpenAPI != OpenAPI30Version {
+		t.Errorf("openapi = %q", doc.OpenAPI)
+	}
+	wantServers := []Server{{URL: "https://petstore.example.com/v1"}, {URL: "http://petstore.example.com/v1"}}
+	if !reflect.DeepEqual(doc.Servers, wantServers) {
+		t.Errorf("servers = %+v, want %+v", doc.Servers, wantServers)
+	}
+	if got, want := doc.OrderedPaths(), []string{"/pets", "/pets/{petId}/photo", "/pets/{petId}/name"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("paths = %q, want %q", got, want)
+	}
+	if got, want := doc.Components.OrderedSchemas(), []string{"Pet", "Cat", "Owner", "Error"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("schemas = %q, want %q", got, want)
+	}
+
+	list := doc.Paths["/pets"].Get
+	if list.Responses["404"].Content[MIMEApplicationJSON].Schema.Ref != "#/components/schemas/Error" {
+		t.Errorf("404 response = %+v, want the NotFound response with an Error schema", list.Responses["404"])
+	}
+	if got := list.Responses["200"].Content[MIMEApplicationJSON].Schema.Items.Ref; got != "#/components/schemas/Pet" {
+		t.Errorf("200 items $ref = %q", got)
+	}
+
+	create := doc.Paths["/pets"].Post
+	if body := create.RequestBody; body == nil || !body.Required || body.Content[MIMEApplicationJSON].Schema.Ref != "#/components/schemas/Pet" {
+		t.Errorf("createPet request body = %+v", body)
+	}
+	if !reflect.DeepEqual(create.Security, []SecurityRequirement{{"petstore_auth": {"write:pets"}}}) {
+		t.Errorf("createPet security = %v", create.Security)
+	}
+
+	pet := doc.Components.Schemas["Pet"]
+	if pet.Discriminator == nil || pet.Discriminator.PropertyName != "petType" {
+		t.Errorf("Pet discriminator = %+v", pet.Discriminator)
+	}
+	if got := pet.Properties["owner"].Ref; got != "#/components/schemas/Owner/properties/name" {
+		t.Errorf("nested $ref = %q", got)
+	}
+	if got := doc.Components.Schemas["Cat"].AllOf[0].Ref; got != "#/components/schemas/Pet" {
+		t.Errorf("Cat allOf $ref = %q", got)
+	}
+}
+
+func TestFromSwagger2CollectionFormat(t *testing.T) {
+	params := make(map[string]Parameter)
+	for _, param := range petstoreSwagger2(t).Paths["/pets"].Get.Parameters {
+		params[param.Name] = param
+	}
+	tests := []struct {
+		name    string
+		style   string
+		explode bool
+	}{
+		{"limit", "", false},
+		{"tags", "form", true},
+		{"ids", "form", false},
+		{"fields", "simple", false},
+		{"words", "spaceDelimited", false},
+		{"flags", "pipeDelimited", false},
+	}
+	for _, tt := range tests {
+		param, ok := params[tt.name]
+		if !ok {
+			t.Errorf("parameter %q is missing", tt.name)
+			continue
+		}
+		if param.Style != tt.style || param.Explode != tt.explode {
+			t.Errorf("%s: style = %q, explode = %v, want %q, %v", tt.name, param.Style, param.Explode, tt.style, tt.explode)
+		}
+	}
+	if limit := params["limit"].Schema; !limit.Type.Is("integer") || limit.Format != "int32" || limit.Maximum == nil || *limit.Maximum != 100 {
+		t.Errorf("limit schema = %+v", limit)
+	}
+	if items := params["tags"].Schema.Items; items == nil || !items.Type.Is("string") {
+		t.Errorf("tags items = %+v", items)
+	}
+}
+
+func TestFromSwagger2FormParameters(t *testing.T) {
+	doc := petstoreSwagger2(t)
+
+	upload := doc.Paths["/pets/{petId}/photo"]
+	if len(upload.Parameters) != 1 || upload.Parameters[0].Name != "petId" || upload.Parameters[0].In != ParamInPath {
+		t.Errorf("path parameters = %+v", upload.Parameters)
+	}
+	body := upload.Post.RequestBody
+	if body == nil || !body.Required {
+		t.Fatalf("uploadPhoto request body = %+v", body)
+	}
+	if got := sortedKeys(body.Content); !reflect.DeepEqual(got, []string{MIMEMultipartFormData}) {
+		t.Errorf("uploadPhoto content types = %q", got)
+	}
+	schema := body.Content[MIMEMultipartFormData].Schema
+	if file := schema.Properties["file"]; !isBinarySchema(file) {
+		t.Errorf("file property = %+v, want string/binary", file)
+	}
+	if !reflect.DeepEqual(schema.Required, []string{"file"}) {
+		t.Errorf("required = %q", schema.Required)
+	}
+
+	rename := doc.Paths["/pets/{petId}/name"].Put.RequestBody
+	if got := sortedKeys(rename.Content); !reflect.DeepEqual(got, []string{MIMEFormURLEncoded}) {
+		t.Errorf("renamePet content types = %q, want urlencoded without a file field", got)
+	}
+}
+
+func TestFromSwagger2SecurityDefinitions(t *testing.T) {
+	schemes := petstoreSwagger2(t).Components.SecuritySchemes
+
+	if got := schemes["api_key"]; got.Type != "apiKey" || got.Name != "X-API-Key" || got.In != ParamInHeader {
+		t.Errorf("api_key = %+v", got)
+	}
+	if got := schemes["basic"]; got.Type != "http" || got.Scheme != "basic" {
+		t.Errorf("basic = %+v", got)
+	}
+	oauth := schemes["petstore_auth"]
+	if oauth.Type != "oauth2" || oauth.Flows == nil || oauth.Flows.AuthorizationCode == nil {
+		t.Fatalf("petstore_auth = %+v", oauth)
+	}
+	flow := oauth.Flows.AuthorizationCode
+	if flow.AuthorizationURL != "https://auth.example.com/authorize" || flow.TokenURL != "https://auth.example.com/token" || len(flow.Scopes) != 2 {
+		t.Errorf("authorization code flow = %+v", flow)
+	}
+}
+
+func TestFromSwagger2Extensions(t *testing.T) {
+	doc := petstoreSwagger2(t)
+	pets := doc.Paths["/pets"]
+	list := pets.Get
+	params := make(map[string]Parameter)
+	for _, param := range list.Parameters {
+		params[param.Name] = param
+	}
+	upload := doc.Paths["/pets/{petId}/photo"].Post.RequestBody.Content[MIMEMultipartFormData].Schema
+
+	tests := []struct {
+		name string
+		ext  Extensions
+		key  string
+		want any
+	}{
+		{"document", doc.Extensions, "x-tenant", "shop"},
+		{"info", doc.Info.Extensions, "x-logo", "logo.png"},
+		{"path item", pets.Extensions, "x-group", "pets"},
+		{"operation order", list.Extensions, ExtOrder, 2},
+		{"operation author", list.Extensions, ExtAuthor, "alice"},
+		{"referenced parameter", params["limit"].Extensions, "x-max-page", 10},
+		{"parameter", params["tags"].Extensions, "x-example-values", []any{"cat", "dog"}},
+		{"body parameter", pets.Post.RequestBody.Extensions, "x-body-note", "full pet"},
+		{"form parameter", upload.Properties["file"].Extensions, "x-max-size", "5MB"},
+		{"response", list.Responses["200"].Extensions, "x-cache", "60s"},
+		{"header", list.Responses["200"].Headers["X-Total"].Extensions, "x-unit", "count"},
+		{"security scheme", doc.Components.SecuritySchemes["api_key"].Extensions, "x-rotate", "daily"},
+		{"schema", doc.Components.Schemas["Pet"].Extensions, "x-entity", "pet"},
+	}
+	for _, tt := range tests {
+		if got, _ := tt.ext.Get(tt.key); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s %s = %#v, want %#v", tt.name, tt.key, got, tt.want)
+		}
+	}
+	if order, ok := list.Extensions.Order(); !ok || order != 2 {
+		t.Errorf("listPets order = %d, %v", order, ok)
+	}
+	for key := range list.Extensions {
+		if key != ExtOrder && key != ExtAuthor {
+			t.Errorf("unexpected operation extension %q", key)
+		}
+	}
+}
+
+func TestFromSwagger2JSON(t *testing.T) {
+	data := []byte(`{
+		"swagger": "2.0",
+		"info": {"title": "t", "version": "1"},
+		"paths": {
+			"/ping": {"get": {
+				"x-order": "3",
+				"parameters": [{"name": "q", "in": "query", "type": "string", "x-hint": "search"}],
+				"responses": {"200": {"description": "pong"}}
+			}}
+		}
+	}`)
+	doc, err := FromSwagger2(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	op := doc.Paths["/ping"].Get
+	if order, ok := op.Extensions.Order(); !ok || order != 3 {
+		t.Errorf("order = %d, %v", order, ok)
+	}
+	if got := op.Parameters[0].Extensions.GetString("x-hint"); got != "search" {
+		t.Errorf("x-hint = %q", got)
+	}
+	if len(doc.Servers) != 1 || doc.Servers[0].URL != "/" {
+		t.Errorf("servers = %+v", doc.Servers)
+	}
+
+	if _, err := FromSwagger2([]byte(`{"swagger": "3.0", "info": {}}`)); err == nil {
+		t.Error("expected an error for an unsupported version")
+	}
+}
+
+var update = flag.Bool("update", false, "rewrite golden files in testdata")
+
+// TestExportSwagger2Golden 将 Swagger 2.0 示例导入后再导出，与 testdata 中的期望结果逐字节比较并校验结构。
+// 修改导出逻辑后可使用 go test -run TestExportSwagger2Golden -update 重新生成期望结果
+func TestExportSwagger2Golden(t *testing.T) {
+	data, warnings, err := ExportSwagger2(petstoreSwagger2(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(warnings) > 0 {
+		t.Errorf("unexpected warnings: %v", warnings)
+	}
+
+	const golden = "testdata/petstore-swagger2.golden.json"
+	if *update {
+		if err := os.WriteFile(golden, append(data, '\n'), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(append(data, '\n')) != string(want) {
+		t.Errorf("export differs from %s:\n%s", golden, data)
+	}
+
+	var tree map[string]any
+	if err := json.Unmarshal(data, &tree); err != nil {
+		t.Fatal(err)
+	}
+	validateSwagger2(t, tree)
+}
+
+func TestSwagger2DocsKeepExtensions(t *testing.T) {
+	doc := &OpenAPI3{
+		OpenAPI: "3.0.3",
+		Info:    Info{Title: "t", Version: "1", Name: "internal-name"},
+		Paths: map[string]PathItem{
+			"/orders": {Get: &Operation{
+				Description: "@order: 3",
+				Parameters:  []Parameter{{Name: "q", In: ParamInQuery, Schema: stringSchema(), Extensions: Extensions{"x-hint": "search"}}},
+				Responses:   map[string]Response{"200": {Description: "OK"}},
+				Extensions:  Extensions{ExtAuthor: "bob", "x-audit": true},
+			}},
+		},
+	}
+	tree := serveJSON(t, &Config{OpenAPI: doc, EnableSwagger2: true}, "/v2/api-docs")
+	validateSwagger2(t, tree)
+
+	if keysOf(tree["info"])["name"] {
+		t.Errorf("info = %v, want no name", tree["info"])
+	}
+	op := lookup(tree, "paths", "/orders", "get")
+	want := map[string]any{ExtOrder: float64(3), ExtAuthor: "bob", "x-audit": true}
+	for key, value := range want {
+		if got := lookup(op, key); got != value {
+			t.Errorf("%s = %v, want %v", key, got, value)
+		}
+	}
+	if got := lookup(op.(map[string]any)["parameters"].([]any)[0], "x-hint"); got != "search" {
+		t.Errorf("parameter x-hint = %v", got)
+	}
+}
+
+// swagger2Fields 列出 Swagger 2.0 各对象允许的字段，x- 扩展字段另行放行
+var swagger2Fields = map[string]map[string]bool{
+	"root": stringSet([]string{"swagger", "info", "host", "basePath", "schemes", "consumes", "produces", "paths",
+		"definitions", "parameters", "responses", "securityDefinitions", "security", "tags", "externalDocs"}),
+	"info":      stringSet([]string{"title", "description", "termsOfService", "contact", "license", "version"}),
+	"pathItem":  stringSet([]string{"$ref", "get", "put", "post", "delete", "options", "head", "patch", "parameters"}),
+	"operation": stringSet([]string{"tags", "summary", "description", "externalDocs", "operationId", "consumes", "produces", "parameters", "responses", "schemes", "deprecated", "security"}),
+	"parameter": stringSet([]string{"name", "in", "description", "required", "schema", "type", "format", "allowEmptyValue", "items",
+		"collectionFormat", "default", "maximum", "exclusiveMaximum", "minimum", "exclusiveMinimum", "maxLength", "minLength",
+		"pattern", "maxItems", "minItems", "uniqueItems", "enum", "multipleOf"}),
+	"response": stringSet([]string{"description", "schema", "headers", "examples"}),
+	"security": stringSet([]string{"type", "description", "name", "in", "flow", "authorizationUrl", "tokenUrl", "scopes"}),
+}
+
+// validateSwagger2 按 Swagger 2.0 规范校验导出结果：各对象只含规范字段与 x- 扩展字段，参数与安全定义取值合法
+func validateSwagger2(t *testing.T, tree map[string]any) {
+	t.Helper()
+	check := func(kind, pointer string, value any) map[string]any {
+		object, ok := value.(map[string]any)
+		if !ok {
+			t.Errorf("%s: %s is not an object", pointer, kind)
+			return nil
+		}
+		for key := range object {
+			if !swagger2Fields[kind][key] && !strings.HasPrefix(key, "x-") {
+				t.Errorf("%s: %s has non-standard field %q", pointer, kind, key)
+			}
+		}
+		return object
+	}
+	parameter := func(pointer string, value any) {
+		param := check("parameter", pointer, value)
+		if param == nil || param["$ref"] != nil {
+			return
+		}
+		switch param["in"] {
+		case ParamInBody:
+			if param["schema"] == nil {
+				t.Errorf("%s: body parameter without schema", pointer)
+			}
+		case ParamInQuery, ParamInHeader, ParamInPath, ParamInFormData:
+			if param["type"] == nil {
+				t.Errorf("%s: parameter without type", pointer)
+			}
+			if param["type"] == ParamTypeFile && param["in"] != ParamInFormData {
+				t.Errorf("%s: file parameter outside formData", pointer)
+			}
+		default:
+			t.Errorf("%s: invalid location %v", pointer, param["in"])
+		}
+		if param["in"] == ParamInPath && param["required"] != true {
+			t.Errorf("%s: path parameter must be required", pointer)
+		}
+	}
+
+	check("root", "#", tree)
+	if tree["swagger"] != "2.0" {
+		t.Errorf("swagger = %v", tree["swagger"])
+	}
+	check("info", "#/info", tree["info"])
+	for name, def := range asObject(tree["securityDefinitions"]) {
+		scheme := check("security", "#/securityDefinitions/"+name, def)
+		if scheme != nil && !stringSet([]string{"basic", "apiKey", "oauth2"})[scheme["type"].(string)] {
+			t.Errorf("#/securityDefinitions/%s: invalid type %v", name, scheme["type"])
+		}
+	}
+	for name, param := range asObject(tree["parameters"]) {
+		parameter("#/parameters/"+name, param)
+	}
+	for name, resp := range asObject(tree["responses"]) {
+		check("response", "#/responses/"+name, resp)
+	}
+	for path, value := range asObject(tree["paths"]) {
+		pointer := "#/paths/" + escapePointer(path)
+		item := check("pathItem", pointer, value)
+		for i, param := range asList(item["parameters"]) {
+			parameter(pointer+"/parameters/"+strconv.Itoa(i), param)
+		}
+		for method, value := range item {
+			if method == "parameters" || method == "$ref" || strings.HasPrefix(method, "x-") {
+				continue
+			}
+			op := check("operation", pointer+"/"+method, value)
+			for i, param := range asList(op["parameters"]) {
+				parameter(pointer+"/"+method+"/parameters/"+strconv.Itoa(i), param)
+			}
+			responses := asObject(op["responses"])
+			if len(responses) == 0 {
+				t.Errorf("%s/%s: no responses", pointer, method)
+			}
+			for code, resp := range responses {
+				check("response", pointer+"/"+method+"/responses/"+code, resp)
+			}
+		}
+	}
+}
+
+func asObject(value any) map[string]any {
+	object, _ := value.(map[string]any)
+	return object
+}
+
+func asList(value any) []any {
+	list, _ := value.([]any)
+	return list
+}
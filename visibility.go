@@ -0,0 +1,284 @@
+package knife4g
+
+import "strings"
+
+// filterVisibility 返回去除 @hidden 标注内容的文档副本，showInternal 为 false 时同时去除 @internal 标注内容。
+// 作用于 tags（同时去除其下的全部接口）、接口、回调、路径级与接口参数以及 Schema 属性，
+// 并删除只被去除内容引用的 components.schemas，原文档保持不变
+func filterVisibility(doc *OpenAPI3, showInternal bool) *OpenAPI3 {
+	invisible := func(parser *CommentParser) bool {
+		return parser.GetBool(TagHidden) || (!showInternal && parser.GetBool(TagInternal))
+	}
+
+	result := rewriteDocumentSchemas(doc, func(pointer string, schema *Schema) *Schema {
+		for name, prop := range schema.Properties {
+			if prop != nil && invisible(schemaAnnotations(prop)) {
+				delete(schema.Properties, name)
+				schema.Required = removeString(schema.Required, name)
+			}
+		}
+		return schema
+	})
+
+	// 隐藏的分组及其下的接口
+	hiddenTags := make(map[string]bool)
+	tags := make([]Tag, 0, len(result.Tags))
+	for _, tag := range result.Tags {
+		parser := tagAnnotations(&tag)
+		if invisible(parser) {
+			hiddenTags[tag.Name] = true
+			if name := parser.GetString(TagTags); name != "" {
+				hiddenTags[name] = true
+			}
+			continue
+		}
+		tags = append(tags, tag)
+	}
+	if result.Tags != nil {
+		result.Tags = tags
+	}
+
+	filter := &visibilityFilter{invisible: invisible, hiddenTags: hiddenTags}
+	for path, item := range result.Paths {
+		if item, ok := filter.pathItem(item); ok {
+			result.Paths[path] = item
+		} else {
+			delete(result.Paths, path)
+		}
+	}
+
+	pruneUnreachableSchemas(doc, result)
+	return result
+}
+
+// visibilityFilter 从文档副本的路径项中去除不可见的接口与参数
+type visibilityFilter struct {
+	invisible  func(parser *CommentParser) bool
+	hiddenTags map[string]bool
+}
+
+// pathItem 去除路径项中不可见的接口、路径级参数与接口参数，并递归处理回调；
+// 路径项原有接口全部被去除时返回 false
+func (f *visibilityFilter) pathItem(item PathItem) (PathItem, bool) {
+	item.Parameters = f.parameters(item.Parameters)
+	removed := false
+	for _, op := range item.operations() {
+		if f.invisible(operationAnnotations(op.operation)) || hasAnyTag(op.operation, f.hiddenTags) {
+			item.removeOperation(op.method)
+			removed = true
+			continue
+		}
+		op.operation.Parameters = f.parameters(op.operation.Parameters)
+		for name, callback := range op.operation.Callbacks {
+			for expr, callbackItem := range callback {
+				if callbackItem, ok := f.pathItem(callbackItem); ok {
+					callback[expr] = callbackItem
+				} else {
+					delete(callback, expr)
+				}
+			}
+			if len(callback) == 0 {
+				delete(op.operation.Callbacks, name)
+			}
+		}
+	}
+	return item, !removed || len(item.operations()) > 0
+}
+
+// parameters 就地去除不可见的参数，params 须为文档副本中的切片
+func (f *visibilityFilter) parameters(params []Parameter) []Parameter {
+	if params == nil {
+		return nil
+	}
+	result := params[:0]
+	for _, param := range params {
+		if !f.invisible(parameterAnnotations(&param)) {
+			result = append(result, param)
+		}
+	}
+	return result
+}
+
+// pruneUnreachableSchemas 从过滤后的文档中删除过滤前可达、过滤后不再被引用的 components.schemas，
+// 避免仅供隐藏接口使用的模型出现在 Knife4j 的模型列表中。原本就未被引用的 Schema 保持不变
+func pruneUnreachableSchemas(before, after *OpenAPI3) {
+	reachable := reachableSchemas(after)
+	for name := range reachableSchemas(before) {
+		if !reachable[name] {
+			delete(after.Components.Schemas, name)
+		}
+	}
+}
+
+// reachableSchemas 返回从 paths、webhooks 与 components 中的参数、响应、请求体和响应头出发，
+// 沿 $ref、discriminator.mapping 以及 @response、@oneOf、@anyOf、@discriminator 标注可达的 components.schemas 名称
+func reachableSchemas(doc *OpenAPI3) map[string]bool {
+	roots := make(schemaRefs)
+	for _, item := range doc.Paths {
+		roots.pathItem(item)
+	}
+	for _, item := range doc.Webhooks {
+		roots.pathItem(item)
+	}
+	for _, param := range doc.Components.Parameters {
+		roots.parameter(param)
+	}
+	for _, resp := range doc.Components.Responses {
+		roots.response(resp)
+	}
+	for _, body := range doc.Components.RequestBodies {
+		roots.content(body.Content)
+	}
+	for _, header := range doc.Components.Headers {
+		roots.header(header)
+	}
+
+	// 沿 components.schemas 之间的引用传递
+	reachable := make(map[string]bool)
+	queue := sortedKeys(roots)
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		if reachable[name] {
+			continue
+		}
+		reachable[name] = true
+		if schema, exists := doc.Components.Schemas[name]; exists {
+			nested := make(schemaRefs)
+			nested.schema(&schema)
+			queue = append(queue, sortedKeys(nested)...)
+		}
+	}
+	return reachable
+}
+
+// schemaRefs 收集引用到的 components.schemas 名称
+type schemaRefs map[string]bool
+
+func (r schemaRefs) add(ref string) {
+	if name, ok := strings.CutPrefix(componentSchemaRef(ref), componentsSchemasPrefix); ok && name != "" {
+		r[name] = true
+	}
+}
+
+func (r schemaRefs) pathItem(item PathItem) {
+	for _, param := range item.Parameters {
+		r.parameter(param)
+	}
+	for _, op := range item.operations() {
+		for _, param := range op.operation.Parameters {
+			r.parameter(param)
+		}
+		if op.operation.RequestBody != nil {
+			r.content(op.operation.RequestBody.Content)
+		}
+		for _, resp := range op.operation.Responses {
+			r.response(resp)
+		}
+		for _, annotation := range operationAnnotations(op.operation).GetResponseAnnotations() {
+			if annotation.Schema != "" {
+				r.add(annotation.Schema)
+			}
+		}
+		for _, callback := range op.operation.Callbacks {
+			for _, callbackItem := range callback {
+				r.pathItem(callbackItem)
+			}
+		}
+	}
+}
+
+func (r schemaRefs) parameter(param Parameter) {
+	r.schema(param.Schema)
+	r.content(param.Content)
+}
+
+func (r schemaRefs) response(resp Response) {
+	r.content(resp.Content)
+	for _, header := range resp.Headers {
+		r.header(header)
+	}
+}
+
+func (r schemaRefs) header(header Header) {
+	r.schema(header.Schema)
+	r.content(header.Content)
+}
+
+func (r schemaRefs) content(content map[string]MediaType) {
+	for _, media := range content {
+		r.schema(media.Schema)
+	}
+}
+
+func (r schemaRefs) schema(schema *Schema) {
+	if schema == nil {
+		return
+	}
+	if schema.Ref != "" {
+		r.add(schema.Ref)
+	}
+	if schema.Discriminator != nil {
+		for _, ref := range schema.Discriminator.Mapping {
+			r.add(ref)
+		}
+	}
+	parser := schemaAnnotations(schema)
+	for _, tag := range []string{"oneOf", "anyOf"} {
+		for _, name := range parser.GetArray(tag) {
+			r.add(name)
+		}
+	}
+	if parser.HasTag("discriminator") {
+		if _, mapping, err := parseDiscriminatorAnnotation(parser.GetString("discriminator")); err == nil {
+			for _, entry := range mapping {
+				r.add(entry[1])
+			}
+		}
+	}
+
+	for _, prop := range schema.Properties {
+		r.schema(prop)
+	}
+	for _, def := range schema.Defs {
+		r.schema(def)
+	}
+	r.schema(schema.Items)
+	r.schema(schema.AdditionalItems)
+	r.schema(schema.Not)
+	for _, list := range [][]*Schema{schema.AllOf, schema.OneOf, schema.AnyOf} {
+		for _, item := range list {
+			r.schema(item)
+		}
+	}
+	if schema.AdditionalProperties != nil {
+		r.schema(schema.AdditionalProperties.Schema)
+	}
+}
+
+// hasAnyTag 判断接口（含 @tags 标注覆盖后的分组）是否属于 tags 中的任一分组
+func hasAnyTag(op *Operation, tags map[string]bool) bool {
+	if len(tags) == 0 {
+		return false
+	}
+	names := op.Tags
+	if override := operationAnnotations(op).GetArray(TagTags); len(override) > 0 {
+		names = override
+	}
+	for _, name := range names {
+		if tags[name] {
+			return true
+		}
+	}
+	return false
+}
+
+func removeString(values []string, target string) []string {
+	result := values[:0]
+	for _, v := range values {
+		if v != target {
+			result = append(result, v)
+		}
+	}
+	return result
+}
@@ -0,0 +1,162 @@
+package knife4g
+
+import (
+	"net/http"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// visibilityDoc 返回含隐藏分组、内部接口、隐藏与内部参数及字段的文档：
+// AdminUser、Stats、Cost、Legacy 只被隐藏或内部内容引用，Orphan 原本就未被引用
+func visibilityDoc() *OpenAPI3 {
+	response := func(name string) map[string]Response {
+		return map[string]Response{"200": {Description: "OK", Content: map[string]MediaType{MIMEApplicationJSON: {Schema: refSchema(name)}}}}
+	}
+	order := Schema{Type: SchemaType{"object"}, Required: []string{"id", "cost", "legacy"}}
+	order.SetProperty("id", stringSchema())
+	order.SetProperty("cost", &Schema{Ref: "#/components/schemas/Cost", Description: "成本\n@internal"})
+	order.SetProperty("legacy", &Schema{Ref: "#/components/schemas/Legacy", Description: "@hidden"})
+
+	return &OpenAPI3{
+		OpenAPI: "3.0.3",
+		Info:    Info{Title: "t", Version: "1"},
+		Tags: []Tag{
+			{Name: "admin", Description: "管理\n@hidden"},
+			{Name: "orders"},
+		},
+		Paths: map[string]PathItem{
+			"/admin/users": {Get: &Operation{Tags: []string{"admin"}, Responses: response("AdminUser")}},
+			"/orders": {
+				Parameters: []Parameter{{Name: "X-Trace", In: ParamInHeader, Description: "@hidden", Schema: stringSchema()}},
+				Get: &Operation{
+					Tags: []string{"orders"},
+					Parameters: []Parameter{
+						{Name: "q", In: ParamInQuery, Schema: stringSchema()},
+						{Name: "debug", In: ParamInQuery, Description: "@internal", Schema: stringSchema()},
+						{Name: "secret", In: ParamInQuery, Description: "@hidden", Schema: stringSchema()},
+					},
+					Responses: response("Order"),
+				},
+				Delete: &Operation{Tags: []string{"orders"}, Description: "@hidden", Responses: response("Order")},
+			},
+			"/stats": {Get: &Operation{Tags: []string{"orders"}, Description: "@internal", Responses: response("Stats")}},
+		},
+		Components: Components{Schemas: map[string]Schema{
+			"Order":     order,
+			"Cost":      *objectSchema(map[string]*Schema{"amount": {Type: SchemaType{"number"}}}),
+			"Legacy":    *objectSchema(map[string]*Schema{"code": stringSchema()}),
+			"AdminUser": *objectSchema(map[string]*Schema{"name": stringSchema()}),
+			"Stats":     *objectSchema(map[string]*Schema{"count": {Type: SchemaType{"integer"}}}),
+			"Orphan":    *objectSchema(map[string]*Schema{"note": stringSchema()}),
+		}},
+	}
+}
+
+// visibleContent 汇总文档中可见的路径与方法、分组、参数、Order 字段及组件 Schema
+type visibleContent struct {
+	operations, tags, params, orderFields, schemas []string
+}
+
+func TestVisibility(t *testing.T) {
+	public := visibleContent{
+		operations:  []string{"get /orders"},
+		tags:        []string{"orders"},
+		params:      []string{"q"},
+		orderFields: []string{"id"},
+		schemas:     []string{"Order", "Orphan"},
+	}
+	internal := visibleContent{
+		operations:  []string{"get /orders", "get /stats"},
+		tags:        []string{"orders"},
+		params:      []string{"q", "debug"},
+		orderFields: []string{"id", "cost"},
+		schemas:     []string{"Cost", "Order", "Orphan", "Stats"},
+	}
+
+	cfg := &Config{
+		OpenAPI:        visibilityDoc(),
+		EnableSwagger2: true,
+		InternalViewer: func(r *http.Request) bool { return r.URL.Query().Get("staff") == "1" },
+	}
+	tests := []struct {
+		target string
+		want   visibleContent
+	}{
+		{"/v3/api-docs", public},
+		{"/v3/api-docs?staff=1", internal},
+		{"/v3/api-docs?raw=true", public},
+		{"/v3/api-docs?raw=true&staff=1", internal},
+		{"/v2/api-docs", public},
+		{"/v2/api-docs?staff=1", internal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			doc := serveJSON(t, cfg, tt.target)
+			got := collectVisible(doc)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got  %+v\nwant %+v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("no viewer", func(t *testing.T) {
+		got := collectVisible(serveJSON(t, &Config{OpenAPI: visibilityDoc()}, "/v3/api-docs?staff=1"))
+		if !reflect.DeepEqual(got, public) {
+			t.Errorf("got  %+v\nwant %+v", got, public)
+		}
+	})
+}
+
+func TestFilterVisibilityKeepsSource(t *testing.T) {
+	doc := visibilityDoc()
+	filterVisibility(doc, false)
+	if got := len(doc.Components.Schemas); got != 6 {
+		t.Errorf("source schemas = %d, want 6", got)
+	}
+	if got := len(doc.Paths["/orders"].Get.Parameters); got != 3 {
+		t.Errorf("source parameters = %d, want 3", got)
+	}
+	order := doc.Components.Schemas["Order"]
+	if got := order.OrderedProperties(); len(got) != 3 {
+		t.Errorf("source Order properties = %q", got)
+	}
+}
+
+// collectVisible 从 JSON 文档（OpenAPI 3 或 Swagger 2.0）中汇总可见内容
+func collectVisible(doc map[string]any) visibleContent {
+	var result visibleContent
+	for path, item := range asObject(doc["paths"]) {
+		for method, op := range asObject(item) {
+			if method == "parameters" {
+				continue
+			}
+			result.operations = append(result.operations, method+" "+path)
+			if path != "/orders" || method != "get" {
+				continue
+			}
+			params := append(asList(lookup(item, "parameters")), asList(lookup(op, "parameters"))...)
+			for _, param := range params {
+				result.params = append(result.params, lookup(param, "name").(string))
+			}
+		}
+	}
+	for _, tag := range asList(doc["tags"]) {
+		result.tags = append(result.tags, lookup(tag, "name").(string))
+	}
+
+	schemas := asObject(lookup(doc, "components", "schemas"))
+	if schemas == nil {
+		schemas = asObject(doc["definitions"])
+	}
+	for name := range schemas {
+		result.schemas = append(result.schemas, name)
+	}
+	for field := range asObject(lookup(schemas["Order"], "properties")) {
+		result.orderFields = append(result.orderFields, field)
+	}
+	sort.Strings(result.operations)
+	sort.Strings(result.schemas)
+	sort.Slice(result.orderFields, func(i, j int) bool { return result.orderFields[i] > result.orderFields[j] })
+	return result
+}
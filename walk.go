@@ -0,0 +1,231 @@
+package knife4g
+
+import (
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// schemaVisitor 在 Schema 树自底向上重写时调用：接收子节点已处理完毕的浅拷贝及其 JSON Pointer，返回替换后的节点
+type schemaVisitor func(pointer string, schema *Schema) *Schema
+
+// rewriteDocumentSchemas 复制整份文档并对其中出现的每个 Schema 节点调用 visit，原文档保持不变。
+// 各节点按源文件顺序（无顺序信息时按字母序）访问，visit 的副作用与 map 遍历顺序无关；
+// 顺序切片截断容量，在副本上追加键时不会写入原文档
+func rewriteDocumentSchemas(doc *OpenAPI3, visit schemaVisitor) *OpenAPI3 {
+	clone := *doc
+	clone.pathOrder = slices.Clip(doc.pathOrder)
+
+	if doc.Paths != nil {
+		clone.Paths = make(map[string]PathItem, len(doc.Paths))
+		for _, path := range doc.OrderedPaths() {
+			clone.Paths[path] = rewritePathItem("#/paths/"+escapePointer(path), doc.Paths[path], visit)
+		}
+	}
+	if doc.Webhooks != nil {
+		clone.Webhooks = make(map[string]PathItem, len(doc.Webhooks))
+		for _, name := range sortedKeys(doc.Webhooks) {
+			clone.Webhooks[name] = rewritePathItem("#/webhooks/"+escapePointer(name), doc.Webhooks[name], visit)
+		}
+	}
+
+	components := doc.Components
+	components.schemaOrder = slices.Clip(doc.Components.schemaOrder)
+	if doc.Components.Schemas != nil {
+		components.Schemas = make(map[string]Schema, len(doc.Components.Schemas))
+		for _, name := range doc.Components.OrderedSchemas() {
+			schema := doc.Components.Schemas[name]
+			components.Schemas[name] = *rewriteSchema("#/components/schemas/"+escapePointer(name), &schema, visit)
+		}
+	}
+	if doc.Components.Parameters != nil {
+		components.Parameters = make(map[string]Parameter, len(doc.Components.Parameters))
+		for _, name := range sortedKeys(doc.Components.Parameters) {
+			components.Parameters[name] = rewriteParameter("#/components/parameters/"+escapePointer(name), doc.Components.Parameters[name], visit)
+		}
+	}
+	if doc.Components.Responses != nil {
+		components.Responses = make(map[string]Response, len(doc.Components.Responses))
+		for _, name := range sortedKeys(doc.Components.Responses) {
+			components.Responses[name] = rewriteResponse("#/components/responses/"+escapePointer(name), doc.Components.Responses[name], visit)
+		}
+	}
+	if doc.Components.RequestBodies != nil {
+		components.RequestBodies = make(map[string]RequestBody, len(doc.Components.RequestBodies))
+		for _, name := range sortedKeys(doc.Components.RequestBodies) {
+			components.RequestBodies[name] = rewriteRequestBody("#/components/requestBodies/"+escapePointer(name), doc.Components.RequestBodies[name], visit)
+		}
+	}
+	if doc.Components.Headers != nil {
+		components.Headers = make(map[string]Header, len(doc.Components.Headers))
+		for _, name := range sortedKeys(doc.Components.Headers) {
+			components.Headers[name] = rewriteHeader("#/components/headers/"+escapePointer(name), doc.Components.Headers[name], visit)
+		}
+	}
+	clone.Components = components
+
+	return &clone
+}
+
+// rewriteSchema 递归复制 Schema 及其全部子节点，并自底向上调用 visit
+func rewriteSchema(pointer string, schema *Schema, visit schemaVisitor) *Schema {
+	if schema == nil {
+		return nil
+	}
+	clone := *schema
+	clone.propertyOrder = slices.Clip(schema.propertyOrder)
+
+	if schema.Properties != nil {
+		clone.Properties = make(map[string]*Schema, len(schema.Properties))
+		for _, name := range schema.OrderedProperties() {
+			clone.Properties[name] = rewriteSchema(pointer+"/properties/"+escapePointer(name), schema.Properties[name], visit)
+		}
+	}
+	if schema.Defs != nil {
+		clone.Defs = make(map[string]*Schema, len(schema.Defs))
+		for _, name := range sortedKeys(schema.Defs) {
+			clone.Defs[name] = rewriteSchema(pointer+"/$defs/"+escapePointer(name), schema.Defs[name], visit)
+		}
+	}
+	clone.Items = rewriteSchema(pointer+"/items", schema.Items, visit)
+	clone.AdditionalItems = rewriteSchema(pointer+"/additionalItems", schema.AdditionalItems, visit)
+	clone.Not = rewriteSchema(pointer+"/not", schema.Not, visit)
+	clone.AllOf = rewriteSchemaList(pointer+"/allOf", schema.AllOf, visit)
+	clone.OneOf = rewriteSchemaList(pointer+"/oneOf", schema.OneOf, visit)
+	clone.AnyOf = rewriteSchemaList(pointer+"/anyOf", schema.AnyOf, visit)
+	if schema.AdditionalProperties != nil {
+		additional := *schema.AdditionalProperties
+		additional.Schema = rewriteSchema(pointer+"/additionalProperties", additional.Schema, visit)
+		clone.AdditionalProperties = &additional
+	}
+	if schema.Discriminator != nil {
+		discriminator := *schema.Discriminator
+		discriminator.Mapping = maps.Clone(schema.Discriminator.Mapping)
+		clone.Discriminator = &discriminator
+	}
+
+	// 切片字段单独复制，避免 visit 修改时影响原文档
+	clone.Type = append(SchemaType(nil), schema.Type...)
+	clone.Required = append([]string(nil), schema.Required...)
+	clone.Enum = append([]interface{}(nil), schema.Enum...)
+	clone.Examples = append([]interface{}(nil), schema.Examples...)
+
+	return visit(pointer, &clone)
+}
+
+func rewriteSchemaList(pointer string, schemas []*Schema, visit schemaVisitor) []*Schema {
+	if schemas == nil {
+		return nil
+	}
+	result := make([]*Schema, len(schemas))
+	for i, item := range schemas {
+		result[i] = rewriteSchema(pointer+"/"+strconv.Itoa(i), item, visit)
+	}
+	return result
+}
+
+func rewritePathItem(pointer string, item PathItem, visit schemaVisitor) PathItem {
+	clone := item
+	clone.Parameters = rewriteParameters(pointer+"/parameters", item.Parameters, visit)
+	clone.Get = rewriteOperation(pointer+"/get", item.Get, visit)
+	clone.Put = rewriteOperation(pointer+"/put", item.Put, visit)
+	clone.Post = rewriteOperation(pointer+"/post", item.Post, visit)
+	clone.Delete = rewriteOperation(pointer+"/delete", item.Delete, visit)
+	clone.Patch = rewriteOperation(pointer+"/patch", item.Patch, visit)
+	return clone
+}
+
+func rewriteOperation(pointer string, op *Operation, visit schemaVisitor) *Operation {
+	if op == nil {
+		return nil
+	}
+	clone := *op
+	clone.Parameters = rewriteParameters(pointer+"/parameters", op.Parameters, visit)
+	if op.RequestBody != nil {
+		body := rewriteRequestBody(pointer+"/requestBody", *op.RequestBody, visit)
+		clone.RequestBody = &body
+	}
+	if op.Responses != nil {
+		clone.Responses = make(map[string]Response, len(op.Responses))
+		for _, code := range op.OrderedResponses() {
+			clone.Responses[code] = rewriteResponse(pointer+"/responses/"+escapePointer(code), op.Responses[code], visit)
+		}
+	}
+	if op.Callbacks != nil {
+		clone.Callbacks = make(map[string]Callback, len(op.Callbacks))
+		for _, name := range sortedKeys(op.Callbacks) {
+			callback := op.Callbacks[name]
+			items := make(Callback, len(callback))
+			for _, expr := range sortedKeys(callback) {
+				items[expr] = rewritePathItem(pointer+"/callbacks/"+escapePointer(name)+"/"+escapePointer(expr), callback[expr], visit)
+			}
+			clone.Callbacks[name] = items
+		}
+	}
+	return &clone
+}
+
+func rewriteParameters(pointer string, params []Parameter, visit schemaVisitor) []Parameter {
+	if params == nil {
+		return nil
+	}
+	result := make([]Parameter, len(params))
+	for i, param := range params {
+		result[i] = rewriteParameter(pointer+"/"+strconv.Itoa(i), param, visit)
+	}
+	return result
+}
+
+func rewriteParameter(pointer string, param Parameter, visit schemaVisitor) Parameter {
+	param.Schema = rewriteSchema(pointer+"/schema", param.Schema, visit)
+	param.Content = rewriteContent(pointer+"/content", param.Content, sortedKeys(param.Content), visit)
+	return param
+}
+
+func rewriteRequestBody(pointer string, body RequestBody, visit schemaVisitor) RequestBody {
+	body.Content = rewriteContent(pointer+"/content", body.Content, body.OrderedContentTypes(), visit)
+	return body
+}
+
+func rewriteResponse(pointer string, resp Response, visit schemaVisitor) Response {
+	resp.Content = rewriteContent(pointer+"/content", resp.Content, resp.OrderedContentTypes(), visit)
+	if resp.Headers != nil {
+		headers := make(map[string]Header, len(resp.Headers))
+		for _, name := range resp.OrderedHeaders() {
+			headers[name] = rewriteHeader(pointer+"/headers/"+escapePointer(name), resp.Headers[name], visit)
+		}
+		resp.Headers = headers
+	}
+	return resp
+}
+
+func rewriteHeader(pointer string, header Header, visit schemaVisitor) Header {
+	header.Schema = rewriteSchema(pointer+"/schema", header.Schema, visit)
+	header.Content = rewriteContent(pointer+"/content", header.Content, sortedKeys(header.Content), visit)
+	return header
+}
+
+// rewriteContent 按 contentTypes 给出的顺序重写 content 中各媒体类型的 Schema
+func rewriteContent(pointer string, content map[string]MediaType, contentTypes []string, visit schemaVisitor) map[string]MediaType {
+	if content == nil {
+		return nil
+	}
+	result := make(map[string]MediaType, len(content))
+	for _, contentType := range contentTypes {
+		media := content[contentType]
+		media.Schema = rewriteSchema(pointer+"/"+escapePointer(contentType)+"/schema", media.Schema, visit)
+		result[contentType] = media
+	}
+	return result
+}
+
+// escapePointer 按 RFC 6901 转义 JSON Pointer 中的路径片段
+func escapePointer(segment string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(segment)
+}
+
+// unescapePointer 还原 escapePointer 转义的路径片段
+func unescapePointer(segment string) string {
+	return strings.NewReplacer("~1", "/", "~0", "~").Replace(segment)
+}